@@ -0,0 +1,439 @@
+package goqrius
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// BinaryVersion is the version of the binary encoding written by MarshalBinary.
+const BinaryVersion byte = 1
+
+// maxBinaryDepth limits the nesting of the decoded expressions, as encoding/json does.
+const maxBinaryDepth = 10000
+
+var (
+	// ErrInvalidBinary is returned when the data does not describe a valid Expression, see UnmarshalBinary.
+	ErrInvalidBinary = errors.New("invalid binary expression")
+	// ErrUnsupportedBinaryVersion is returned when the data was encoded with a version that can't be decoded.
+	ErrUnsupportedBinaryVersion = errors.New("unsupported binary expression version")
+)
+
+// MarshalBinary encodes expr in a compact binary format, so it can be forwarded to another service
+// that decodes it with UnmarshalBinary, without parsing the filter expression again.
+//
+// The format starts with the BinaryVersion, followed by the nodes in depth-first order.
+// Every node is written as its tag, its fields, and its Span,
+// with the strings prefixed by their length and the numbers as uvarints.
+func MarshalBinary(expr Expression) ([]byte, error) {
+	return appendBinary([]byte{BinaryVersion}, expr)
+}
+
+// UnmarshalBinary decodes an Expression encoded with MarshalBinary.
+// Only the root can be nil, a node with a nil child returns ErrInvalidBinary.
+func UnmarshalBinary(data []byte) (Expression, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: empty data", ErrInvalidBinary)
+	}
+
+	if data[0] != BinaryVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedBinaryVersion, data[0])
+	}
+
+	d := &binaryDecoder{data: data, pos: 1}
+
+	expr, err := d.expression(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrInvalidBinary, len(d.data)-d.pos)
+	}
+
+	return expr, nil
+}
+
+func (ae *AndExpr) MarshalBinary() ([]byte, error)    { return MarshalBinary(ae) }
+func (ae *AndExpr) UnmarshalBinary(data []byte) error { return unmarshalBinaryNode(data, ae) }
+
+func (oe *OrExpr) MarshalBinary() ([]byte, error)    { return MarshalBinary(oe) }
+func (oe *OrExpr) UnmarshalBinary(data []byte) error { return unmarshalBinaryNode(data, oe) }
+
+func (ne *NotExpr) MarshalBinary() ([]byte, error)    { return MarshalBinary(ne) }
+func (ne *NotExpr) UnmarshalBinary(data []byte) error { return unmarshalBinaryNode(data, ne) }
+
+func (ie *FilterExpr) MarshalBinary() ([]byte, error)    { return MarshalBinary(ie) }
+func (ie *FilterExpr) UnmarshalBinary(data []byte) error { return unmarshalBinaryNode(data, ie) }
+
+func (i *Identifier) MarshalBinary() ([]byte, error)    { return MarshalBinary(i) }
+func (i *Identifier) UnmarshalBinary(data []byte) error { return unmarshalBinaryNode(data, i) }
+
+func (il *IntegerLiteral) MarshalBinary() ([]byte, error)    { return MarshalBinary(il) }
+func (il *IntegerLiteral) UnmarshalBinary(data []byte) error { return unmarshalBinaryNode(data, il) }
+
+func (bl *BooleanLiteral) MarshalBinary() ([]byte, error)    { return MarshalBinary(bl) }
+func (bl *BooleanLiteral) UnmarshalBinary(data []byte) error { return unmarshalBinaryNode(data, bl) }
+
+func (n *Null) MarshalBinary() ([]byte, error)    { return MarshalBinary(n) }
+func (n *Null) UnmarshalBinary(data []byte) error { return unmarshalBinaryNode(data, n) }
+
+func (sl *StringLiteral) MarshalBinary() ([]byte, error)    { return MarshalBinary(sl) }
+func (sl *StringLiteral) UnmarshalBinary(data []byte) error { return unmarshalBinaryNode(data, sl) }
+
+func (be *BadExpr) MarshalBinary() ([]byte, error)    { return MarshalBinary(be) }
+func (be *BadExpr) UnmarshalBinary(data []byte) error { return unmarshalBinaryNode(data, be) }
+
+func (me *MissingExpr) MarshalBinary() ([]byte, error)    { return MarshalBinary(me) }
+func (me *MissingExpr) UnmarshalBinary(data []byte) error { return unmarshalBinaryNode(data, me) }
+
+// unmarshalBinaryNode decodes data into dst, failing if data describes another type of node.
+func unmarshalBinaryNode[T any, P interface {
+	*T
+	Expression
+}](data []byte, dst P) error {
+	expr, err := UnmarshalBinary(data)
+	if err != nil {
+		return err
+	}
+
+	src, ok := expr.(P)
+	if !ok {
+		return fmt.Errorf("%w: can not decode %T into %T", ErrInvalidBinary, expr, dst)
+	}
+
+	*dst = *src
+
+	return nil
+}
+
+// appendBinary appends the encoding of expr to b.
+// The tags of the nodes are the same ones used by Hash, that are stable across versions.
+func appendBinary(b []byte, expr Expression) ([]byte, error) {
+	var err error
+
+	switch e := expr.(type) {
+	case nil:
+		return append(b, hashNil), nil
+	case *AndExpr:
+		b, err = appendBinaryChildren(append(b, hashAnd), e.Left, e.Right)
+		if err != nil {
+			return nil, err
+		}
+
+		return appendSpan(b, e.Span), nil
+	case *OrExpr:
+		b, err = appendBinaryChildren(append(b, hashOr), e.Left, e.Right)
+		if err != nil {
+			return nil, err
+		}
+
+		return appendSpan(b, e.Span), nil
+	case *NotExpr:
+		b, err = appendBinary(append(b, hashNot), e.Right)
+		if err != nil {
+			return nil, err
+		}
+
+		return appendSpan(b, e.Span), nil
+	case *FilterExpr:
+		b = appendString(append(b, hashFilter), string(e.Operator))
+
+		var left Expression
+		if e.Left != nil {
+			left = e.Left
+		}
+
+		var right Expression
+		if e.Right != nil {
+			right = e.Right
+		}
+
+		b, err = appendBinaryChildren(b, left, right)
+		if err != nil {
+			return nil, err
+		}
+
+		return appendSpan(b, e.Span), nil
+	case *Identifier:
+		return appendSpan(appendString(append(b, hashIdentifier), e.Value), e.Span), nil
+	case *IntegerLiteral:
+		return appendSpan(appendString(append(b, hashInteger), e.Value), e.Span), nil
+	case *StringLiteral:
+		return appendSpan(appendString(append(b, hashString), e.Value), e.Span), nil
+	case *BooleanLiteral:
+		return appendSpan(append(b, hashBoolean, boolByte(e.Value)), e.Span), nil
+	case *Null:
+		return appendSpan(append(b, hashNull), e.Span), nil
+	case *BadExpr:
+		return appendSpan(append(b, hashBad), e.Span), nil
+	case *MissingExpr:
+		return appendSpan(append(b, hashMissing), e.Span), nil
+	default:
+		return nil, fmt.Errorf("%w: unexpected expression type %T", ErrInvalidBinary, expr)
+	}
+}
+
+func appendBinaryChildren(b []byte, left, right Expression) ([]byte, error) {
+	b, err := appendBinary(b, left)
+	if err != nil {
+		return nil, err
+	}
+
+	return appendBinary(b, right)
+}
+
+func appendString(b []byte, s string) []byte {
+	return append(binary.AppendUvarint(b, uint64(len(s))), s...)
+}
+
+func appendSpan(b []byte, span Span) []byte {
+	return binary.AppendUvarint(binary.AppendUvarint(b, uint64(span.Start)), uint64(span.End)) //nolint:gosec // spans are never negative.
+}
+
+type binaryDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *binaryDecoder) expression(depth int) (Expression, error) {
+	if depth > maxBinaryDepth {
+		return nil, fmt.Errorf("%w: exceeded max depth", ErrInvalidBinary)
+	}
+
+	tag, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tag {
+	case hashNil:
+		//nolint:nilnil // the nil tag is the encoding of a nil Expression.
+		return nil, nil
+	case hashAnd, hashOr:
+		return d.logical(tag, depth)
+	case hashNot:
+		return d.not(depth)
+	case hashFilter:
+		return d.filter(depth)
+	case hashIdentifier, hashInteger, hashString:
+		return d.literal(tag)
+	case hashBoolean:
+		return d.boolean()
+	case hashNull, hashBad, hashMissing:
+		return d.leaf(tag)
+	default:
+		return nil, fmt.Errorf("%w: unknown tag %d at %d", ErrInvalidBinary, tag, d.pos-1)
+	}
+}
+
+func (d *binaryDecoder) logical(tag byte, depth int) (Expression, error) {
+	left, right, err := d.children(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	span, err := d.span()
+	if err != nil {
+		return nil, err
+	}
+
+	if tag == hashAnd {
+		return &AndExpr{Left: left, Right: right, Span: span}, nil
+	}
+
+	return &OrExpr{Left: left, Right: right, Span: span}, nil
+}
+
+func (d *binaryDecoder) not(depth int) (Expression, error) {
+	right, err := d.child(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	span, err := d.span()
+	if err != nil {
+		return nil, err
+	}
+
+	return &NotExpr{Right: right, Span: span}, nil
+}
+
+func (d *binaryDecoder) boolean() (Expression, error) {
+	b, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+
+	if b > 1 {
+		return nil, fmt.Errorf("%w: invalid boolean %d at %d", ErrInvalidBinary, b, d.pos-1)
+	}
+
+	span, err := d.span()
+	if err != nil {
+		return nil, err
+	}
+
+	return &BooleanLiteral{Value: b == 1, Span: span}, nil
+}
+
+func (d *binaryDecoder) leaf(tag byte) (Expression, error) {
+	span, err := d.span()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tag {
+	case hashNull:
+		return &Null{Span: span}, nil
+	case hashBad:
+		return &BadExpr{Span: span}, nil
+	default:
+		return &MissingExpr{Span: span}, nil
+	}
+}
+
+func (d *binaryDecoder) children(depth int) (Expression, Expression, error) {
+	left, err := d.child(depth)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	right, err := d.child(depth)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return left, right, nil
+}
+
+// child decodes a child of a node, that can't be nil.
+func (d *binaryDecoder) child(depth int) (Expression, error) {
+	pos := d.pos
+
+	expr, err := d.expression(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if expr == nil {
+		return nil, fmt.Errorf("%w: missing child at %d", ErrInvalidBinary, pos)
+	}
+
+	return expr, nil
+}
+
+func (d *binaryDecoder) filter(depth int) (Expression, error) {
+	operator, err := d.string()
+	if err != nil {
+		return nil, err
+	}
+
+	switch FilterOperator(operator) {
+	case Eq, NotEq, GreaterThan, GreaterThanOrEqual, LessThan, LessThanOrEqual:
+	default:
+		return nil, fmt.Errorf("%w: unknown operator %q", ErrInvalidBinary, operator)
+	}
+
+	left, right, err := d.children(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	ident, ok := left.(*Identifier)
+	if !ok {
+		return nil, fmt.Errorf("%w: left side of a filter must be an identifier, got %T", ErrInvalidBinary, left)
+	}
+
+	value, ok := right.(Value)
+	if !ok {
+		return nil, fmt.Errorf("%w: right side of a filter must be a value, got %T", ErrInvalidBinary, right)
+	}
+
+	filter := &FilterExpr{Left: ident, Operator: FilterOperator(operator), Right: value}
+
+	filter.Span, err = d.span()
+	if err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+func (d *binaryDecoder) literal(tag byte) (Expression, error) {
+	value, err := d.string()
+	if err != nil {
+		return nil, err
+	}
+
+	span, err := d.span()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tag {
+	case hashIdentifier:
+		return &Identifier{Value: value, Span: span}, nil
+	case hashInteger:
+		if !isDigits(value) {
+			return nil, fmt.Errorf("%w: %q is not an integer", ErrInvalidBinary, value)
+		}
+
+		return &IntegerLiteral{Value: value, Span: span}, nil
+	default:
+		return &StringLiteral{Value: value, Span: span}, nil
+	}
+}
+
+func (d *binaryDecoder) byte() (byte, error) {
+	if d.pos >= len(d.data) {
+		return 0, fmt.Errorf("%w: unexpected end of data", ErrInvalidBinary)
+	}
+
+	b := d.data[d.pos]
+	d.pos++
+
+	return b, nil
+}
+
+func (d *binaryDecoder) uvarint() (int, error) {
+	v, n := binary.Uvarint(d.data[d.pos:])
+	if n <= 0 || v > math.MaxInt {
+		return 0, fmt.Errorf("%w: invalid number at %d", ErrInvalidBinary, d.pos)
+	}
+
+	d.pos += n
+
+	return int(v), nil
+}
+
+func (d *binaryDecoder) string() (string, error) {
+	n, err := d.uvarint()
+	if err != nil {
+		return "", err
+	}
+
+	if n > len(d.data)-d.pos {
+		return "", fmt.Errorf("%w: unexpected end of data", ErrInvalidBinary)
+	}
+
+	s := string(d.data[d.pos : d.pos+n])
+	d.pos += n
+
+	return s, nil
+}
+
+func (d *binaryDecoder) span() (Span, error) {
+	start, err := d.uvarint()
+	if err != nil {
+		return Span{}, err
+	}
+
+	end, err := d.uvarint()
+	if err != nil {
+		return Span{}, err
+	}
+
+	return Span{Start: start, End: end}, nil
+}
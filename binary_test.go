@@ -0,0 +1,245 @@
+package goqrius
+
+import (
+	"encoding"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = new(FilterExpr)
+	_ encoding.BinaryUnmarshaler = new(FilterExpr)
+	_ encoding.BinaryMarshaler   = new(BooleanLiteral)
+	_ encoding.BinaryUnmarshaler = new(BooleanLiteral)
+)
+
+func TestMarshalBinary(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"simple filter":     "name eq 'John'",
+		"logical operators": "name eq 'John' and (age lt 18 or age gt 65) and not email eq null",
+		"escaped string":    "name eq 'O''Neil'",
+		"empty string":      "name eq ''",
+		"long string":       "description ne '" + strings.Repeat("x", 300) + "'",
+		"nested not":        "not not not a eq 1",
+		"partial tree":      "name eq and age gt",
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, _ := Parse(input)
+
+			data, err := MarshalBinary(expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if data[0] != BinaryVersion {
+				t.Fatalf("unexpected version %d", data[0])
+			}
+
+			decoded, err := UnmarshalBinary(data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !Equal(decoded, expr) {
+				t.Fatalf("decoded expression is not equal. expected=%s got=%s", expr, decoded)
+			}
+
+			if decoded.Pos() != expr.Pos() || decoded.End() != expr.End() {
+				t.Fatalf("unexpected span. expected=[%d,%d) got=[%d,%d)", expr.Pos(), expr.End(), decoded.Pos(), decoded.End())
+			}
+		})
+	}
+}
+
+func TestMarshalBinaryFormat(t *testing.T) {
+	t.Parallel()
+
+	expr := &FilterExpr{Left: &Identifier{Value: "a"}, Operator: Eq, Right: &IntegerLiteral{Value: "1"}}
+
+	data, err := expr.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []byte{
+		BinaryVersion,
+		hashFilter, 2, 'e', 'q',
+		hashIdentifier, 1, 'a', 0, 0,
+		hashInteger, 1, '1', 0, 0,
+		0, 0,
+	}
+	if string(data) != string(expected) {
+		t.Fatalf("unexpected encoding.\nexpected=%v\ngot=     %v", expected, data)
+	}
+
+	var decoded FilterExpr
+	if err = decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !Equal(&decoded, expr) {
+		t.Fatalf("unexpected decoded filter %s", &decoded)
+	}
+}
+
+func TestMarshalBinaryNil(t *testing.T) {
+	t.Parallel()
+
+	data, err := MarshalBinary(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expr, err := UnmarshalBinary(data)
+	if err != nil || expr != nil {
+		t.Fatalf("expected a nil expression, got %v, %v", expr, err)
+	}
+}
+
+func TestUnmarshalBinaryErrors(t *testing.T) {
+	t.Parallel()
+
+	valid, _ := MarshalBinary(MustParse("a eq 1"))
+
+	deep := []byte{BinaryVersion}
+	for range maxBinaryDepth + 1 {
+		deep = append(deep, hashNot)
+	}
+
+	tests := map[string]struct {
+		data     []byte
+		expected error
+	}{
+		"empty": {
+			data:     nil,
+			expected: ErrInvalidBinary,
+		},
+		"unsupported version": {
+			data:     append([]byte{BinaryVersion + 1}, valid[1:]...),
+			expected: ErrUnsupportedBinaryVersion,
+		},
+		"truncated": {
+			data:     valid[:len(valid)-3],
+			expected: ErrInvalidBinary,
+		},
+		"trailing bytes": {
+			data:     append(append([]byte{}, valid...), 0),
+			expected: ErrInvalidBinary,
+		},
+		"unknown tag": {
+			data:     []byte{BinaryVersion, 255},
+			expected: ErrInvalidBinary,
+		},
+		"unknown operator": {
+			data:     []byte{BinaryVersion, hashFilter, 2, 'x', 'x', hashNil, hashNil, 0, 0},
+			expected: ErrInvalidBinary,
+		},
+		"left is not identifier": {
+			data:     []byte{BinaryVersion, hashFilter, 2, 'e', 'q', hashNull, 0, 0, hashNil, 0, 0},
+			expected: ErrInvalidBinary,
+		},
+		"logical without children": {
+			data:     []byte{BinaryVersion, hashAnd, hashNil, hashNil, 0, 0},
+			expected: ErrInvalidBinary,
+		},
+		"not without child": {
+			data:     []byte{BinaryVersion, hashNot, hashNil, 0, 0},
+			expected: ErrInvalidBinary,
+		},
+		"filter without value": {
+			data:     []byte{BinaryVersion, hashFilter, 2, 'e', 'q', hashIdentifier, 1, 'a', 0, 1, hashNil, 0, 0},
+			expected: ErrInvalidBinary,
+		},
+		"integer with letters": {
+			data:     []byte{BinaryVersion, hashInteger, 5, '1', '2', 'a', 'b', 'c', 0, 5},
+			expected: ErrInvalidBinary,
+		},
+		"empty integer": {
+			data:     []byte{BinaryVersion, hashInteger, 0, 0, 0},
+			expected: ErrInvalidBinary,
+		},
+		"string longer than data": {
+			data:     []byte{BinaryVersion, hashString, 10, 'a'},
+			expected: ErrInvalidBinary,
+		},
+		"too deep": {
+			data:     deep,
+			expected: ErrInvalidBinary,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := UnmarshalBinary(tt.data); !errors.Is(err, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestMarshalBinaryNodes(t *testing.T) {
+	t.Parallel()
+
+	span := Span{Start: 1, End: 5}
+	ident := &Identifier{Value: "a", Span: span}
+	filter := &FilterExpr{Left: ident, Operator: Eq, Right: &IntegerLiteral{Value: "1", Span: span}, Span: span}
+
+	tests := map[string]struct {
+		node    encoding.BinaryMarshaler
+		decoded encoding.BinaryUnmarshaler
+	}{
+		"and":        {node: &AndExpr{Left: filter, Right: filter, Span: span}, decoded: new(AndExpr)},
+		"or":         {node: &OrExpr{Left: filter, Right: filter, Span: span}, decoded: new(OrExpr)},
+		"not":        {node: &NotExpr{Right: filter, Span: span}, decoded: new(NotExpr)},
+		"filter":     {node: filter, decoded: new(FilterExpr)},
+		"identifier": {node: ident, decoded: new(Identifier)},
+		"integer":    {node: &IntegerLiteral{Value: "18", Span: span}, decoded: new(IntegerLiteral)},
+		"string":     {node: &StringLiteral{Value: "John", Span: span}, decoded: new(StringLiteral)},
+		"boolean":    {node: &BooleanLiteral{Value: true, Span: span}, decoded: new(BooleanLiteral)},
+		"null":       {node: &Null{Span: span}, decoded: new(Null)},
+		"bad":        {node: &BadExpr{Span: span}, decoded: new(BadExpr)},
+		"missing":    {node: &MissingExpr{Span: span}, decoded: new(MissingExpr)},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := tt.node.MarshalBinary()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if err = tt.decoded.UnmarshalBinary(data); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			node, _ := tt.node.(Expression)
+			decoded, _ := tt.decoded.(Expression)
+
+			if !Equal(decoded, node) || decoded.Pos() != node.Pos() || decoded.End() != node.End() {
+				t.Fatalf("unexpected node. expected=%v got=%v", node, decoded)
+			}
+		})
+	}
+}
+
+func TestUnmarshalBinaryWrongNode(t *testing.T) {
+	t.Parallel()
+
+	data, _ := MarshalBinary(MustParse("a eq 1 or b eq 2"))
+
+	var and AndExpr
+	if err := and.UnmarshalBinary(data); !errors.Is(err, ErrInvalidBinary) {
+		t.Fatalf("expected ErrInvalidBinary, got %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+package goqrius
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/golaxo/goqrius/internal/lexer"
+	"github.com/golaxo/goqrius/internal/token"
+)
+
+// ErrUnsupportedLiteral is returned when a Go value can not be converted to a Value, see Literal.
+var ErrUnsupportedLiteral = errors.New("unsupported literal")
+
+// FieldRef builds the comparisons of a field, see Field.
+type FieldRef struct {
+	name string
+}
+
+// Field starts a comparison of the field name, e.g. `Field("age").Gt(18)` is the same as `age gt 18`.
+// It panics if name is not a valid identifier.
+func Field(name string) FieldRef {
+	tok := lexer.New(name).NextToken()
+	if tok.Type != token.Ident || tok.Literal != name {
+		panic(fmt.Sprintf("goqrius: invalid field name %q", name))
+	}
+
+	return FieldRef{name: name}
+}
+
+// Eq returns `field eq v`, see Literal for the supported values. It panics if v is not supported.
+func (f FieldRef) Eq(v any) *FilterExpr { return f.compare(Eq, v) }
+
+// Ne returns `field ne v`, see Literal for the supported values. It panics if v is not supported.
+func (f FieldRef) Ne(v any) *FilterExpr { return f.compare(NotEq, v) }
+
+// Gt returns `field gt v`, see Literal for the supported values. It panics if v is not supported.
+func (f FieldRef) Gt(v any) *FilterExpr { return f.compare(GreaterThan, v) }
+
+// Ge returns `field ge v`, see Literal for the supported values. It panics if v is not supported.
+func (f FieldRef) Ge(v any) *FilterExpr { return f.compare(GreaterThanOrEqual, v) }
+
+// Lt returns `field lt v`, see Literal for the supported values. It panics if v is not supported.
+func (f FieldRef) Lt(v any) *FilterExpr { return f.compare(LessThan, v) }
+
+// Le returns `field le v`, see Literal for the supported values. It panics if v is not supported.
+func (f FieldRef) Le(v any) *FilterExpr { return f.compare(LessThanOrEqual, v) }
+
+func (f FieldRef) compare(operator FilterOperator, v any) *FilterExpr {
+	value, err := Literal(v)
+	if err != nil {
+		panic(fmt.Sprintf("goqrius: %v", err))
+	}
+
+	return &FilterExpr{Left: &Identifier{Value: f.name}, Operator: operator, Right: value}
+}
+
+// And joins the operands with `and`, left associative as the parser does,
+// e.g. `And(a, b, c)` is the same as `a and b and c`.
+// The nil operands are skipped, and nil is returned if there are no operands left.
+// It panics if an operand is a nil pointer, an *Identifier or a literal, that the parser never joins.
+func And(operands ...Expression) Expression {
+	return join(operands, func(left, right Expression) Expression { return &AndExpr{Left: left, Right: right} })
+}
+
+// Or joins the operands with `or`, left associative as the parser does,
+// e.g. `Or(a, b, c)` is the same as `a or b or c`.
+// The nil operands are skipped, and nil is returned if there are no operands left.
+// It panics if an operand is a nil pointer, an *Identifier or a literal, that the parser never joins.
+func Or(operands ...Expression) Expression {
+	return join(operands, func(left, right Expression) Expression { return &OrExpr{Left: left, Right: right} })
+}
+
+// Not returns `not expr`.
+// It panics if expr is nil, a nil pointer, an *Identifier or a literal, that the parser never negates.
+func Not(expr Expression) *NotExpr {
+	if expr == nil {
+		panic("goqrius: not of a nil expression")
+	}
+
+	checkOperand(expr)
+
+	return &NotExpr{Right: expr}
+}
+
+func join(operands []Expression, f func(left, right Expression) Expression) Expression {
+	var result Expression
+
+	for _, operand := range operands {
+		if operand != nil {
+			checkOperand(operand)
+		}
+
+		switch {
+		case operand == nil:
+		case result == nil:
+			result = operand
+		default:
+			result = f(result, operand)
+		}
+	}
+
+	return result
+}
+
+// checkOperand panics if expr can't be an operand of and, or and not.
+func checkOperand(expr Expression) {
+	switch {
+	case isNilNode(expr):
+		panic(fmt.Sprintf("goqrius: nil %T operand", expr))
+	case isLeaf(expr):
+		panic(fmt.Sprintf("goqrius: %T can only be used in a comparison", expr))
+	}
+}
+
+// Literal converts a Go value to the Value node the parser would create for it:
+//   - nil and nil pointers are Null.
+//   - strings are StringLiteral, and time.Time is a StringLiteral with the RFC 3339 format, see StringLiteral.Time.
+//   - booleans are BooleanLiteral.
+//   - non negative integers, of any size, and *big.Int are IntegerLiteral, as the language has no negative numbers.
+//   - Value nodes are returned as they are, except the nil ones, e.g. (*StringLiteral)(nil).
+//
+// Types whose underlying type is one of the above, e.g. `type Status string`, and pointers to them are also supported.
+// Any other type returns ErrUnsupportedLiteral.
+func Literal(v any) (Value, error) {
+	switch x := v.(type) {
+	case nil:
+		return &Null{}, nil
+	case Value:
+		if isNilNode(x) {
+			return nil, fmt.Errorf("%w: nil %T", ErrUnsupportedLiteral, v)
+		}
+
+		return x, nil
+	case Expression:
+		return nil, fmt.Errorf("%w: %T is not a value", ErrUnsupportedLiteral, v)
+	case string:
+		return &StringLiteral{Value: x}, nil
+	case bool:
+		return &BooleanLiteral{Value: x}, nil
+	case time.Time:
+		return &StringLiteral{Value: x.Format(time.RFC3339Nano)}, nil
+	case *big.Int:
+		if x == nil {
+			return &Null{}, nil
+		}
+
+		if x.Sign() < 0 {
+			return nil, fmt.Errorf("%w: negative integer %s", ErrUnsupportedLiteral, x)
+		}
+
+		return &IntegerLiteral{Value: x.String()}, nil
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return &Null{}, nil
+		}
+
+		return Literal(rv.Elem().Interface())
+	case reflect.String:
+		return &StringLiteral{Value: rv.String()}, nil
+	case reflect.Bool:
+		return &BooleanLiteral{Value: rv.Bool()}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rv.Int() < 0 {
+			return nil, fmt.Errorf("%w: negative integer %d", ErrUnsupportedLiteral, rv.Int())
+		}
+
+		return &IntegerLiteral{Value: strconv.FormatInt(rv.Int(), 10)}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &IntegerLiteral{Value: strconv.FormatUint(rv.Uint(), 10)}, nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedLiteral, v)
+	}
+}
@@ -0,0 +1,157 @@
+package goqrius
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	t.Parallel()
+
+	type status string
+
+	name := "John"
+
+	tests := map[string]struct {
+		expr     Expression
+		expected string
+	}{
+		"comparisons": {
+			expr: And(
+				Field("a").Eq(1), Field("b").Ne("x"), Field("c").Gt(2), Field("d").Ge(3), Field("e").Lt(4), Field("f").Le(5),
+			),
+			expected: "a eq 1 and b ne 'x' and c gt 2 and d ge 3 and e lt 4 and f le 5",
+		},
+		"request example": {
+			expr:     And(Field("age").Gt(18), Field("name").Eq("John")),
+			expected: "age gt 18 and name eq 'John'",
+		},
+		"or inside and": {
+			expr:     And(Field("name").Eq("John"), Or(Field("age").Lt(18), Field("age").Gt(65))),
+			expected: "name eq 'John' and (age lt 18 or age gt 65)",
+		},
+		"not": {
+			expr:     Not(Or(Field("a").Eq(1), Field("b").Eq(2))),
+			expected: "not (a eq 1 or b eq 2)",
+		},
+		"null": {
+			expr:     Field("email").Ne(nil),
+			expected: "email ne null",
+		},
+		"escaped string": {
+			expr:     Field("name").Eq("O'Neil"),
+			expected: "name eq 'O''Neil'",
+		},
+		"named types and pointers": {
+			expr:     And(Field("status").Eq(status("active")), Field("name").Eq(&name), Field("id").Eq(uint8(7))),
+			expected: "status eq 'active' and name eq 'John' and id eq 7",
+		},
+		"nil operands are skipped": {
+			expr:     And(nil, Field("a").Eq(1), nil),
+			expected: "a eq 1",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Format(tt.expr); got != tt.expected {
+				t.Fatalf("unexpected expression. expected=%q got=%q", tt.expected, got)
+			}
+
+			if parsed := MustParse(tt.expected); !Equal(tt.expr, parsed) {
+				t.Fatalf("built expression is not equal to the parsed one. expected=%s got=%s", parsed, tt.expr)
+			}
+		})
+	}
+}
+
+func TestBuilderNoOperands(t *testing.T) {
+	t.Parallel()
+
+	if expr := Or(); expr != nil {
+		t.Fatalf("expected nil, got %s", expr)
+	}
+}
+
+func TestLiteral(t *testing.T) {
+	t.Parallel()
+
+	var nilPointer *string
+
+	large, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	tests := map[string]struct {
+		value    any
+		expected Value
+		err      error
+	}{
+		"nil":              {value: nil, expected: &Null{}},
+		"nil pointer":      {value: nilPointer, expected: &Null{}},
+		"string":           {value: "John", expected: &StringLiteral{Value: "John"}},
+		"int":              {value: 18, expected: &IntegerLiteral{Value: "18"}},
+		"int64":            {value: int64(18), expected: &IntegerLiteral{Value: "18"}},
+		"uint64":           {value: uint64(18446744073709551615), expected: &IntegerLiteral{Value: "18446744073709551615"}},
+		"big int":          {value: large, expected: &IntegerLiteral{Value: "123456789012345678901234567890"}},
+		"value":            {value: &StringLiteral{Value: "x"}, expected: &StringLiteral{Value: "x"}},
+		"negative":         {value: -5, err: ErrUnsupportedLiteral},
+		"negative big int": {value: big.NewInt(-5), err: ErrUnsupportedLiteral},
+		"nil value":        {value: (*StringLiteral)(nil), err: ErrUnsupportedLiteral},
+		"expression":       {value: Field("a").Eq(1), err: ErrUnsupportedLiteral},
+		"nil expression":   {value: (*FilterExpr)(nil), err: ErrUnsupportedLiteral},
+		"float":            {value: 1.5, err: ErrUnsupportedLiteral},
+		"slice":            {value: []int{1}, err: ErrUnsupportedLiteral},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Literal(tt.value)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+
+			if tt.err == nil && !Equal(got, tt.expected) {
+				t.Fatalf("unexpected literal. expected=%s got=%s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestBuilderPanics(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]func(){
+		"float value":         func() { Field("a").Eq(1.5) },
+		"negative value":      func() { Field("a").Gt(-1) },
+		"struct value":        func() { Field("a").Lt(struct{}{}) },
+		"slice value":         func() { Field("a").Ne([]string{"x"}) },
+		"nil value":           func() { Field("a").Eq((*StringLiteral)(nil)) },
+		"not of nil":          func() { Not(nil) },
+		"not of a nil node":   func() { Not((*FilterExpr)(nil)) },
+		"not of a literal":    func() { Not(&BooleanLiteral{Value: true}) },
+		"and with a nil node": func() { And(Field("a").Eq(1), (*OrExpr)(nil)) },
+		"and with a field":    func() { And(Field("a").Eq(1), &Identifier{Value: "b"}) },
+		"or with a literal":   func() { Or(&StringLiteral{Value: "x"}, Field("a").Eq(1)) },
+		"invalid field name":  func() { Field("a b") },
+		"keyword field name":  func() { Field("and") },
+		"empty field name":    func() { Field("") },
+	}
+
+	for name, f := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected a panic")
+				}
+			}()
+
+			f()
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Command goqrius works with filter expressions.
+//
+// Usage:
+//
+//	goqrius fmt [-l] [-w] [files...]
+//
+// The fmt command prints the filter expressions in their canonical form, see goqrius.Canonicalize.
+// Every file contains one filter expression, and without files the expression is read from the standard input.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/golaxo/goqrius"
+)
+
+const usage = "usage: goqrius fmt [-l] [-w] [files...]"
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "fmt" {
+		fmt.Fprintln(stderr, usage)
+
+		return 2
+	}
+
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	list := flags.Bool("l", false, "list the files whose formatting differs from the canonical one")
+	write := flags.Bool("w", false, "write the result to the file instead of the standard output")
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	if flags.NArg() == 0 {
+		if *list || *write {
+			fmt.Fprintln(stderr, "goqrius fmt: -l and -w need files")
+
+			return 2
+		}
+
+		input, err := io.ReadAll(stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "goqrius fmt: %v\n", err)
+
+			return 2
+		}
+
+		return formatInput("<stdin>", input, *list, *write, stdout, stderr)
+	}
+
+	code := 0
+
+	for _, name := range flags.Args() {
+		input, err := os.ReadFile(name)
+		if err != nil {
+			fmt.Fprintf(stderr, "goqrius fmt: %v\n", err)
+
+			code = 2
+
+			continue
+		}
+
+		if c := formatInput(name, input, *list, *write, stdout, stderr); c != 0 {
+			code = c
+		}
+	}
+
+	return code
+}
+
+func formatInput(name string, input []byte, list, write bool, stdout, stderr io.Writer) int {
+	src := strings.TrimSpace(string(input))
+
+	formatted, err := goqrius.Canonicalize(src)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s:\n%s", name, goqrius.RenderError(src, err))
+
+		return 2
+	}
+
+	output := []byte(formatted + "\n")
+	changed := !bytes.Equal(input, output)
+
+	if list && changed {
+		fmt.Fprintln(stdout, name)
+	}
+
+	if write {
+		if changed {
+			if err := os.WriteFile(name, output, 0o644); err != nil { //nolint:gosec // keep the usual permissions of a source file.
+				fmt.Fprintf(stderr, "goqrius fmt: %v\n", err)
+
+				return 2
+			}
+		}
+
+		return 0
+	}
+
+	if !list {
+		_, _ = stdout.Write(output)
+	}
+
+	return 0
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunFmt(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args           []string
+		stdin          string
+		expectedCode   int
+		expectedStdout string
+		expectedStderr string
+	}{
+		"stdin is canonicalized": {
+			args:           []string{"fmt"},
+			stdin:          "Name EQ 'John'  AND Age gt 18\n",
+			expectedStdout: "Age gt 18 and Name eq 'John'\n",
+		},
+		"invalid filter": {
+			args:           []string{"fmt"},
+			stdin:          "name eq",
+			expectedCode:   2,
+			expectedStderr: "error: missing value after eq",
+		},
+		"unknown command": {
+			args:           []string{"lint"},
+			expectedCode:   2,
+			expectedStderr: "usage: goqrius fmt",
+		},
+		"list without files": {
+			args:           []string{"fmt", "-l"},
+			expectedCode:   2,
+			expectedStderr: "-l and -w need files",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr bytes.Buffer
+
+			code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if code != tt.expectedCode {
+				t.Fatalf("unexpected exit code. expected=%d got=%d, stderr=%s", tt.expectedCode, code, stderr.String())
+			}
+
+			if stdout.String() != tt.expectedStdout {
+				t.Fatalf("unexpected stdout. expected=%q got=%q", tt.expectedStdout, stdout.String())
+			}
+
+			if !strings.Contains(stderr.String(), tt.expectedStderr) {
+				t.Fatalf("unexpected stderr. expected to contain %q, got=%q", tt.expectedStderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunFmtFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	formatted := filepath.Join(dir, "formatted.filter")
+	unformatted := filepath.Join(dir, "unformatted.filter")
+
+	if err := os.WriteFile(formatted, []byte("a eq 1 and b eq 2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(unformatted, []byte("b EQ 2 AND a eq 1"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"fmt", "-l", "-w", formatted, unformatted}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("unexpected exit code %d, stderr=%s", code, stderr.String())
+	}
+
+	if expected := unformatted + "\n"; stdout.String() != expected {
+		t.Fatalf("unexpected listed files. expected=%q got=%q", expected, stdout.String())
+	}
+
+	got, err := os.ReadFile(unformatted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "a eq 1 and b eq 2\n"; string(got) != expected {
+		t.Fatalf("unexpected file content. expected=%q got=%q", expected, string(got))
+	}
+}
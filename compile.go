@@ -0,0 +1,430 @@
+package goqrius
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//nolint:gochecknoglobals // types compared in a special way.
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	jsonNumberType = reflect.TypeFor[json.Number]()
+	bigIntType     = reflect.TypeFor[big.Int]()
+)
+
+// maxExactFloat is the biggest integer that a float64 represents exactly.
+const maxExactFloat = 1 << 53
+
+type (
+	// predicate returns whether the value matches a compiled expression, unknown if it depends on a null,
+	// and false as second result if it can't be evaluated, where Match returns an error.
+	predicate func(v reflect.Value) (truth, bool)
+
+	// comparison returns whether a non null value matches a literal, and false as second result if they can't be
+	// compared, e.g. a string with an integer.
+	comparison func(v reflect.Value) (bool, bool)
+
+	// accessor returns the value of a field, an invalid value if it's null, and whether it was found.
+	accessor func(v reflect.Value) (reflect.Value, bool)
+
+	compiler struct {
+		options evalOptions
+	}
+)
+
+// Compile returns a predicate reporting whether a T matches expr, following the same semantics as Match.
+//
+// The fields of the structs are resolved, and the literals converted, once, so the returned function is much faster
+// than calling Match for every element of a big slice.
+// The errors that can be known from the type T, e.g. an unknown field or a type mismatch, are returned by Compile.
+// The ones that depend on the values, e.g. a missing key of a map, make the predicate return false,
+// as the whole expression, so a surrounding `not` doesn't turn them into a match.
+func Compile[T any](expr Expression, opts ...EvalOption) (func(T) bool, error) {
+	c := compiler{options: newEvalOptions(opts...)}
+
+	p, err := c.compile(expr, reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+
+	if reflect.TypeFor[T]().Kind() == reflect.Pointer {
+		// a pointer doesn't escape to the heap when it's converted to a reflect.Value.
+		return func(v T) bool {
+			t, ok := p(reflect.ValueOf(v))
+
+			return ok && t == truthTrue
+		}, nil
+	}
+
+	return func(v T) bool {
+		t, ok := p(reflect.ValueOf(&v).Elem())
+
+		return ok && t == truthTrue
+	}, nil
+}
+
+func (c compiler) compile(expr Expression, t reflect.Type) (predicate, error) {
+	switch e := expr.(type) {
+	case nil:
+		return func(reflect.Value) (truth, bool) { return truthTrue, true }, nil
+	case *AndExpr:
+		left, right, err := c.compileBoth(e.Left, e.Right, t)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(v reflect.Value) (truth, bool) {
+			l, ok := left(v)
+			if !ok || l == truthFalse {
+				return truthFalse, ok
+			}
+
+			r, ok := right(v)
+
+			return l.and(r), ok
+		}, nil
+	case *OrExpr:
+		left, right, err := c.compileBoth(e.Left, e.Right, t)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(v reflect.Value) (truth, bool) {
+			l, ok := left(v)
+			if !ok || l == truthTrue {
+				return l, ok
+			}
+
+			r, ok := right(v)
+
+			return l.or(r), ok
+		}, nil
+	case *NotExpr:
+		right, err := c.compile(e.Right, t)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(v reflect.Value) (truth, bool) {
+			r, ok := right(v)
+
+			return r.not(), ok
+		}, nil
+	case *FilterExpr:
+		return c.compileFilter(e, t)
+	default:
+		return nil, fmt.Errorf("%w: %s at position %d", ErrUnsupportedExpression, expr, expr.Pos())
+	}
+}
+
+func (c compiler) compileBoth(left, right Expression, t reflect.Type) (predicate, predicate, error) {
+	l, err := c.compile(left, t)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, err := c.compile(right, t)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return l, r, nil
+}
+
+func (c compiler) compileFilter(f *FilterExpr, t reflect.Type) (predicate, error) {
+	if f.Left == nil || f.Right == nil {
+		return nil, fmt.Errorf("%w: incomplete comparison at position %d", ErrUnsupportedExpression, f.Pos())
+	}
+
+	switch f.Right.(type) {
+	case *BadExpr, *MissingExpr:
+		return nil, fmt.Errorf("%w: %s at position %d", ErrUnsupportedExpression, f.Right, f.Right.Pos())
+	}
+
+	access, fieldType, err := c.compileAccessor(f.Left, t)
+	if err != nil {
+		return nil, err
+	}
+
+	compare, err := compileComparison(f.Operator, fieldType, f.Right)
+	if err != nil {
+		return nil, fmt.Errorf("%q %s %s at position %d: %w", f.Left.Value, f.Operator, f.Right, f.Pos(), err)
+	}
+
+	missingAsNull := c.options.missingAsNull
+	_, isNull := f.Right.(*Null)
+	// result of the comparison when the field is null.
+	nullResult := c.options.nullComparison(f.Operator)
+	if isNull {
+		nullResult = toTruth(f.Operator == Eq)
+	}
+
+	return func(v reflect.Value) (truth, bool) {
+		value, found := access(v)
+		if !found && !missingAsNull {
+			return truthFalse, false
+		}
+
+		value = indirect(value)
+		if !value.IsValid() {
+			return nullResult, true
+		}
+
+		if isNull {
+			return toTruth(f.Operator == NotEq), true
+		}
+
+		matches, ok := compare(value)
+
+		return toTruth(matches), ok
+	}, nil
+}
+
+// compileAccessor resolves the path of ident through the type t,
+// returning the accessor of the field and its type, or nil if it's only known at runtime.
+func (c compiler) compileAccessor(ident *Identifier, t reflect.Type) (accessor, reflect.Type, error) {
+	var steps []accessor
+
+	segments := strings.Split(ident.Value, ".")
+	for i, segment := range segments {
+		for t != nil && t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+
+		if t == nil || t.Kind() != reflect.Struct || t == timeType {
+			if t == nil || t.Kind() == reflect.Interface || (t.Kind() == reflect.Map && t.Key().Kind() == reflect.String) {
+				steps = append(steps, dynamicAccessor(segments[i:]))
+				t = nil
+
+				break
+			}
+
+			return c.notFound(ident)
+		}
+
+		index, ok := structFields(t)[segment]
+		if !ok {
+			return c.notFound(ident)
+		}
+
+		steps = append(steps, func(v reflect.Value) (reflect.Value, bool) {
+			v = indirect(v)
+			if !v.IsValid() {
+				return v, true
+			}
+
+			field, err := v.FieldByIndexErr(index)
+			if err != nil {
+				// reached through a nil embedded pointer.
+				return reflect.Value{}, true
+			}
+
+			return field, true
+		})
+		t = t.FieldByIndex(index).Type
+	}
+
+	return func(v reflect.Value) (reflect.Value, bool) {
+		for _, step := range steps {
+			var found bool
+			if v, found = step(v); !found {
+				return v, false
+			}
+
+			if !v.IsValid() {
+				break
+			}
+		}
+
+		return v, true
+	}, t, nil
+}
+
+// notFound returns the error of an unknown field, or an accessor of null if the missing fields are null.
+func (c compiler) notFound(ident *Identifier) (accessor, reflect.Type, error) {
+	if c.options.missingAsNull {
+		return func(reflect.Value) (reflect.Value, bool) { return reflect.Value{}, true }, nil, nil
+	}
+
+	return nil, nil, fmt.Errorf("%w: %q at position %d", ErrFieldNotFound, ident.Value, ident.Pos())
+}
+
+// dynamicAccessor resolves the segments at runtime, for the maps and interfaces.
+func dynamicAccessor(segments []string) accessor {
+	return func(v reflect.Value) (reflect.Value, bool) {
+		for _, segment := range segments {
+			v = indirect(v)
+			if !v.IsValid() {
+				return v, true
+			}
+
+			var found bool
+			if v, found = lookup(v, segment); !found {
+				return v, false
+			}
+		}
+
+		return v, true
+	}
+}
+
+// compileComparison returns the comparison of a non null value of type t with the literal.
+// If t is nil, the type is only known at runtime.
+//
+//nolint:exhaustive // the other kinds are compared at runtime.
+func compileComparison(operator FilterOperator, t reflect.Type, literal Value) (comparison, error) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if _, isNull := literal.(*Null); isNull {
+		if _, err := applyEquality(operator, true); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	if t == nil || t == jsonNumberType {
+		return dynamicComparison(operator, literal), nil
+	}
+
+	switch lit := literal.(type) {
+	case *IntegerLiteral:
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return compileInt(operator, lit)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return compileUint(operator, lit)
+		case reflect.Float32, reflect.Float64:
+			return compileFloat(operator, lit), nil
+		}
+	case *StringLiteral:
+		if t == timeType {
+			return compileTime(operator, lit)
+		}
+
+		if t.Kind() == reflect.String {
+			return func(v reflect.Value) (bool, bool) {
+				return applyOperator(operator, strings.Compare(v.String(), lit.Value)), true
+			}, nil
+		}
+	case *BooleanLiteral:
+		if t.Kind() == reflect.Bool {
+			if _, err := applyEquality(operator, true); err != nil {
+				return nil, err
+			}
+
+			return func(v reflect.Value) (bool, bool) {
+				equal, _ := applyEquality(operator, v.Bool() == lit.Value)
+
+				return equal, true
+			}, nil
+		}
+	}
+
+	if t.Kind() == reflect.Interface || t == bigIntType {
+		return dynamicComparison(operator, literal), nil
+	}
+
+	return nil, fmt.Errorf("%w: can not compare %s with %s", ErrTypeMismatch, t, literal)
+}
+
+// dynamicComparison compares the values whose type is only known at runtime, as Eval does.
+func dynamicComparison(operator FilterOperator, literal Value) comparison {
+	return func(v reflect.Value) (bool, bool) {
+		if v.Type() == bigIntType && v.CanAddr() {
+			v = v.Addr()
+		}
+
+		if !v.CanInterface() {
+			return false, false
+		}
+
+		matches, err := compareValue(operator, normalize(v.Interface()), literal)
+
+		return matches, err == nil
+	}
+}
+
+func compileInt(operator FilterOperator, lit *IntegerLiteral) (comparison, error) {
+	i, err := lit.Int64()
+	if err != nil {
+		c, err := outOfRange(lit)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(reflect.Value) (bool, bool) { return applyOperator(operator, c), true }, nil
+	}
+
+	return func(v reflect.Value) (bool, bool) { return applyOperator(operator, cmp.Compare(v.Int(), i)), true }, nil
+}
+
+func compileUint(operator FilterOperator, lit *IntegerLiteral) (comparison, error) {
+	u, err := strconv.ParseUint(lit.Value, 10, 64)
+	if err != nil {
+		c, err := outOfRange(lit)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(reflect.Value) (bool, bool) { return applyOperator(operator, c), true }, nil
+	}
+
+	return func(v reflect.Value) (bool, bool) { return applyOperator(operator, cmp.Compare(v.Uint(), u)), true }, nil
+}
+
+func compileFloat(operator FilterOperator, lit *IntegerLiteral) comparison {
+	i, err := lit.Int64()
+	if err != nil || i > maxExactFloat || i < -maxExactFloat {
+		// compare exactly, see compareNumber.
+		return func(v reflect.Value) (bool, bool) {
+			c, err := compareNumber(v.Float(), lit)
+
+			return err == nil && applyOperator(operator, c), err == nil
+		}
+	}
+
+	f := float64(i)
+
+	return func(v reflect.Value) (bool, bool) {
+		x := v.Float()
+		if math.IsNaN(x) {
+			// NaN can't be compared with an integer, see compareNumber.
+			return false, false
+		}
+
+		return applyOperator(operator, cmp.Compare(x, f)), true
+	}
+}
+
+func compileTime(operator FilterOperator, lit *StringLiteral) (comparison, error) {
+	t, err := lit.Time()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q is not a date to compare with a time", ErrTypeMismatch, lit.Value)
+	}
+
+	return func(v reflect.Value) (bool, bool) {
+		value, _ := v.Interface().(time.Time)
+
+		return applyOperator(operator, value.Compare(t)), true
+	}, nil
+}
+
+// outOfRange returns the comparison of any int64 with an integer literal out of its range.
+func outOfRange(lit *IntegerLiteral) (int, error) {
+	b, err := lit.BigInt()
+	if err != nil {
+		return 0, err
+	}
+
+	return -b.Sign(), nil
+}
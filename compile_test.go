@@ -0,0 +1,291 @@
+package goqrius
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+type compileUser struct {
+	Name    string            `json:"name"`
+	Age     int               `json:"age"`
+	Views   uint64            `json:"views"`
+	Score   float64           `json:"score"`
+	Active  bool              `json:"active"`
+	Email   *string           `json:"email"`
+	Created time.Time         `json:"created"`
+	Balance *big.Int          `json:"balance"`
+	Address *matchAddress     `json:"address"`
+	Extra   map[string]any    `json:"extra"`
+	Labels  map[string]string `json:"labels"`
+	Any     any               `json:"any"`
+}
+
+func compileUsers() []compileUser {
+	email := "john.doe@example.com"
+
+	return []compileUser{
+		{
+			Name: "John", Age: 20, Views: 10, Score: 7.5, Active: true, Email: &email,
+			Created: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Balance: big.NewInt(-5),
+			Address: &matchAddress{City: "Madrid"}, Extra: map[string]any{"team": "core", "level": 3},
+			Labels: map[string]string{"role": "admin"}, Any: 1,
+		},
+		{Name: "Jane", Age: 10, Score: 9, Created: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Any: "x"},
+		{Name: "Alice", Age: 66, Views: 18446744073709551615, Address: &matchAddress{City: "Paris"}},
+	}
+}
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+
+	filters := []string{
+		"name eq 'John'",
+		"name gt 'Bob'",
+		"age gt 18 and age lt 65",
+		"age le 18 or age gt 65",
+		"not age eq 20",
+		"age lt 99999999999999999999",
+		"views gt 9223372036854775807",
+		"views ge 0",
+		"score gt 7",
+		"score eq 9",
+		"active eq true",
+		"active ne true",
+		"email eq null",
+		"email ne null",
+		"email eq 'john.doe@example.com'",
+		"email gt 'a'",
+		"created gt '2025-01-01T00:00:00Z'",
+		"balance lt 0",
+		"balance eq null",
+		"address.city eq 'Madrid'",
+		"address.city ne 'Madrid'",
+		"address eq null",
+		"address.zip eq null",
+		"extra.team eq 'core'",
+		"extra.level ge 3",
+		"labels.role eq 'admin'",
+		"any eq 1",
+		"not extra.unknown eq 1",
+		"not extra.team eq 1",
+		"not any eq 'x'",
+		"extra.unknown eq 1 or name eq 'John'",
+	}
+
+	users := compileUsers()
+
+	for _, filter := range filters {
+		t.Run(filter, func(t *testing.T) {
+			t.Parallel()
+
+			expr := MustParse(filter)
+
+			predicate, err := Compile[compileUser](expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			pointerPredicate, err := Compile[*compileUser](expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for i, user := range users {
+				expected, err := Match(expr, user)
+				if err != nil {
+					// the errors that depend on the values make the predicate false.
+					expected = false
+				}
+
+				if got := predicate(user); got != expected {
+					t.Fatalf("user[%d] - unexpected result. expected=%t got=%t", i, expected, got)
+				}
+
+				if got := pointerPredicate(&user); got != expected {
+					t.Fatalf("user[%d] - unexpected result with a pointer. expected=%t got=%t", i, expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCompileMap(t *testing.T) {
+	t.Parallel()
+
+	predicate, err := Compile[map[string]any](MustParse("name eq 'John' and address.city eq 'Madrid'"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !predicate(map[string]any{"name": "John", "address": map[string]any{"city": "Madrid"}}) {
+		t.Fatalf("expected a match")
+	}
+
+	if predicate(map[string]any{"name": "John"}) {
+		t.Fatalf("expected a missing field to not match")
+	}
+}
+
+func TestCompileNotOverErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]map[string]any{
+		"not (a eq 1)":                 {},
+		"not (name eq 1)":              {"name": "x"},
+		"not (a eq 1 and name eq 'x')": {"a": "1", "name": "x"},
+	}
+
+	for filter, value := range tests {
+		t.Run(filter, func(t *testing.T) {
+			t.Parallel()
+
+			expr := MustParse(filter)
+
+			if _, err := Match(expr, value); err == nil {
+				t.Fatalf("expected Match to fail")
+			}
+
+			predicate, err := Compile[map[string]any](expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if predicate(value) {
+				t.Fatalf("expected an expression that can't be evaluated to not match")
+			}
+		})
+	}
+}
+
+func TestCompileNaN(t *testing.T) {
+	t.Parallel()
+
+	user := compileUser{Score: math.NaN()}
+
+	for _, filter := range []string{"score gt 1", "not score gt 1", "score eq 99999999999999999999", "not score ne 1"} {
+		t.Run(filter, func(t *testing.T) {
+			t.Parallel()
+
+			expr := MustParse(filter)
+
+			if _, err := Match(expr, user); !errors.Is(err, ErrTypeMismatch) {
+				t.Fatalf("expected %v, got %v", ErrTypeMismatch, err)
+			}
+
+			predicate, err := Compile[compileUser](expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if predicate(user) {
+				t.Fatalf("expected NaN to not match")
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		expr     Expression
+		expected error
+	}{
+		"unknown field":       {expr: MustParse("surname eq 'Doe'"), expected: ErrFieldNotFound},
+		"unknown nested":      {expr: MustParse("address.zipcode eq 1"), expected: ErrFieldNotFound},
+		"field of a string":   {expr: MustParse("name.first eq 'J'"), expected: ErrFieldNotFound},
+		"string with int":     {expr: MustParse("name eq 1"), expected: ErrTypeMismatch},
+		"int with string":     {expr: MustParse("age eq '1'"), expected: ErrTypeMismatch},
+		"ordered booleans":    {expr: MustParse("active gt true"), expected: ErrTypeMismatch},
+		"time with non date":  {expr: MustParse("created gt 'yesterday'"), expected: ErrTypeMismatch},
+		"struct with string":  {expr: MustParse("address eq 'Madrid'"), expected: ErrTypeMismatch},
+		"partial tree":        {expr: &OrExpr{Left: MustParse("age gt 1"), Right: &BadExpr{}}, expected: ErrUnsupportedExpression},
+		"missing right value": {expr: &FilterExpr{Left: &Identifier{Value: "age"}, Operator: Eq, Right: &MissingExpr{}}, expected: ErrUnsupportedExpression},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Compile[compileUser](tt.expr); !errors.Is(err, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestCompileWithMissingAsNull(t *testing.T) {
+	t.Parallel()
+
+	predicate, err := Compile[compileUser](MustParse("surname eq null and extra.unknown eq null"), WithMissingAsNull())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !predicate(compileUsers()[0]) {
+		t.Fatalf("expected the missing fields to be null")
+	}
+}
+
+func benchmarkUsers(n int) []compileUser {
+	users := make([]compileUser, n)
+	for i := range users {
+		users[i] = compileUser{
+			Name:    fmt.Sprintf("user%d", i),
+			Age:     i % 100,
+			Active:  i%2 == 0,
+			Address: &matchAddress{City: "Madrid"},
+		}
+	}
+
+	return users
+}
+
+const benchmarkFilter = "age gt 18 and age lt 65 and active eq true and (address.city eq 'Madrid' or name eq 'John')"
+
+func BenchmarkCompile(b *testing.B) {
+	users := benchmarkUsers(1000)
+
+	predicate, err := Compile[*compileUser](MustParse(benchmarkFilter))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+
+	for range b.N {
+		for i := range users {
+			predicate(&users[i])
+		}
+	}
+}
+
+func BenchmarkMatch(b *testing.B) {
+	users := benchmarkUsers(1000)
+	expr := MustParse(benchmarkFilter)
+
+	b.ResetTimer()
+
+	for range b.N {
+		for i := range users {
+			_, _ = Match(expr, &users[i])
+		}
+	}
+}
+
+func TestCompileNilPointer(t *testing.T) {
+	t.Parallel()
+
+	predicate, err := Compile[*compileUser](MustParse("name eq null"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !predicate(nil) {
+		t.Fatalf("expected the fields of a nil pointer to be null")
+	}
+}
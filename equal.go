@@ -0,0 +1,213 @@
+package goqrius
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+)
+
+// Equal reports whether a and b are structurally equal, ignoring their spans.
+func Equal(a, b Expression) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	switch x := a.(type) {
+	case *AndExpr:
+		y, ok := b.(*AndExpr)
+
+		return ok && Equal(x.Left, y.Left) && Equal(x.Right, y.Right)
+	case *OrExpr:
+		y, ok := b.(*OrExpr)
+
+		return ok && Equal(x.Left, y.Left) && Equal(x.Right, y.Right)
+	case *NotExpr:
+		y, ok := b.(*NotExpr)
+
+		return ok && Equal(x.Right, y.Right)
+	case *FilterExpr:
+		y, ok := b.(*FilterExpr)
+
+		return ok && x.Operator == y.Operator && equalIdentifier(x.Left, y.Left) && Equal(x.Right, y.Right)
+	case *Identifier:
+		y, ok := b.(*Identifier)
+
+		return ok && x.Value == y.Value
+	case *IntegerLiteral:
+		y, ok := b.(*IntegerLiteral)
+
+		return ok && x.Value == y.Value
+	case *StringLiteral:
+		y, ok := b.(*StringLiteral)
+
+		return ok && x.Value == y.Value
+	case *BooleanLiteral:
+		y, ok := b.(*BooleanLiteral)
+
+		return ok && x.Value == y.Value
+	case *Null:
+		_, ok := b.(*Null)
+
+		return ok
+	case *BadExpr:
+		_, ok := b.(*BadExpr)
+
+		return ok
+	case *MissingExpr:
+		_, ok := b.(*MissingExpr)
+
+		return ok
+	default:
+		return false
+	}
+}
+
+func equalIdentifier(a, b *Identifier) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return a.Value == b.Value
+}
+
+// Clone returns a deep copy of expr.
+func Clone(expr Expression) Expression {
+	switch e := expr.(type) {
+	case nil:
+		return nil
+	case *AndExpr:
+		return &AndExpr{Left: Clone(e.Left), Right: Clone(e.Right), Span: e.Span}
+	case *OrExpr:
+		return &OrExpr{Left: Clone(e.Left), Right: Clone(e.Right), Span: e.Span}
+	case *NotExpr:
+		return &NotExpr{Right: Clone(e.Right), Span: e.Span}
+	case *FilterExpr:
+		c := &FilterExpr{Operator: e.Operator, Span: e.Span}
+		if e.Left != nil {
+			c.Left = &Identifier{Value: e.Left.Value, Span: e.Left.Span}
+		}
+
+		if e.Right != nil {
+			c.Right, _ = Clone(e.Right).(Value)
+		}
+
+		return c
+	case *Identifier:
+		c := *e
+
+		return &c
+	case *IntegerLiteral:
+		c := *e
+
+		return &c
+	case *StringLiteral:
+		c := *e
+
+		return &c
+	case *BooleanLiteral:
+		c := *e
+
+		return &c
+	case *Null:
+		c := *e
+
+		return &c
+	case *BadExpr:
+		c := *e
+
+		return &c
+	case *MissingExpr:
+		c := *e
+
+		return &c
+	default:
+		return expr
+	}
+}
+
+// Node tags used to compute the Hash of an expression, and to encode it with MarshalBinary.
+const (
+	hashNil byte = iota
+	hashAnd
+	hashOr
+	hashNot
+	hashFilter
+	hashIdentifier
+	hashInteger
+	hashString
+	hashNull
+	hashBad
+	hashMissing
+	hashBoolean
+	// new tags must be added at the end, to keep the hashes and the binary encoding stable.
+)
+
+// Hash returns a structural hash of expr, ignoring the spans.
+// Expressions that are Equal have the same Hash, and the hash is stable across processes and versions
+// with the same node types, so it can be used as a fingerprint, e.g. as a cache key.
+func Hash(expr Expression) uint64 {
+	h := fnv.New64a()
+	writeHash(h, expr)
+
+	return h.Sum64()
+}
+
+func writeHash(h hash.Hash64, expr Expression) {
+	switch e := expr.(type) {
+	case nil:
+		h.Write([]byte{hashNil})
+	case *AndExpr:
+		h.Write([]byte{hashAnd})
+		writeHash(h, e.Left)
+		writeHash(h, e.Right)
+	case *OrExpr:
+		h.Write([]byte{hashOr})
+		writeHash(h, e.Left)
+		writeHash(h, e.Right)
+	case *NotExpr:
+		h.Write([]byte{hashNot})
+		writeHash(h, e.Right)
+	case *FilterExpr:
+		h.Write([]byte{hashFilter})
+		writeHashString(h, string(e.Operator))
+
+		if e.Left == nil {
+			h.Write([]byte{hashNil})
+		} else {
+			writeHash(h, e.Left)
+		}
+
+		writeHash(h, e.Right)
+	case *Identifier:
+		h.Write([]byte{hashIdentifier})
+		writeHashString(h, e.Value)
+	case *IntegerLiteral:
+		h.Write([]byte{hashInteger})
+		writeHashString(h, e.Value)
+	case *StringLiteral:
+		h.Write([]byte{hashString})
+		writeHashString(h, e.Value)
+	case *BooleanLiteral:
+		h.Write([]byte{hashBoolean, boolByte(e.Value)})
+	case *Null:
+		h.Write([]byte{hashNull})
+	case *BadExpr:
+		h.Write([]byte{hashBad})
+	case *MissingExpr:
+		h.Write([]byte{hashMissing})
+	}
+}
+
+// writeHashString writes the length before the string, so adjacent strings can't be confused.
+func writeHashString(h hash.Hash64, s string) {
+	h.Write(binary.AppendUvarint(nil, uint64(len(s))))
+	h.Write([]byte(s))
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,104 @@
+package goqrius
+
+import (
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		a, b     string
+		expected bool
+	}{
+		"same input":               {a: "name eq 'John'", b: "name eq 'John'", expected: true},
+		"different whitespaces":    {a: "name eq 'John' and age gt 18", b: "(name  eq 'John') and age gt 18", expected: true},
+		"different value":          {a: "name eq 'John'", b: "name eq 'Jane'", expected: false},
+		"different operator":       {a: "age gt 18", b: "age ge 18", expected: false},
+		"different field":          {a: "age gt 18", b: "size gt 18", expected: false},
+		"different value type":     {a: "age eq 18", b: "age eq '18'", expected: false},
+		"different logical":        {a: "a eq 1 and b eq 2", b: "a eq 1 or b eq 2", expected: false},
+		"different associativity":  {a: "a eq 1 and b eq 2 and c eq 3", b: "a eq 1 and (b eq 2 and c eq 3)", expected: false},
+		"not":                      {a: "not a eq 1", b: "not (a eq 1)", expected: true},
+		"null":                     {a: "a eq null", b: "a eq null", expected: true},
+		"null vs string":           {a: "a eq null", b: "a eq 'null'", expected: false},
+		"different operands order": {a: "a eq 1 or b eq 2", b: "b eq 2 or a eq 1", expected: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			a, b := MustParse(tt.a), MustParse(tt.b)
+
+			if got := Equal(a, b); got != tt.expected {
+				t.Fatalf("expected Equal to be %t, got %t", tt.expected, got)
+			}
+
+			if tt.expected && Hash(a) != Hash(b) {
+				t.Fatalf("expected equal expressions to have the same hash")
+			}
+
+			if !tt.expected && Hash(a) == Hash(b) {
+				t.Fatalf("expected different expressions to have different hashes")
+			}
+		})
+	}
+}
+
+func TestEqualNil(t *testing.T) {
+	t.Parallel()
+
+	if !Equal(nil, nil) {
+		t.Fatalf("expected nil expressions to be equal")
+	}
+
+	if Equal(MustParse("a eq 1"), nil) {
+		t.Fatalf("expected nil and non nil expressions to be different")
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	expr := MustParse("not name eq 'John' and (age gt 18 or email eq null)")
+
+	clone := Clone(expr)
+	if !Equal(expr, clone) {
+		t.Fatalf("expected clone to be equal. expected=%s got=%s", expr, clone)
+	}
+
+	if clone.Pos() != expr.Pos() || clone.End() != expr.End() {
+		t.Fatalf("expected clone to keep the spans")
+	}
+
+	clone.(*AndExpr).Left.(*NotExpr).Right.(*FilterExpr).Left.Value = "surname"
+
+	if expr.String() != "((not (name eq 'John')) and ((age gt 18) or (email eq null)))" {
+		t.Fatalf("expected original to be untouched, got %s", expr)
+	}
+}
+
+func TestHashStable(t *testing.T) {
+	t.Parallel()
+
+	// the hash must not change between versions, so it can be persisted.
+	const expected uint64 = 0xbc2ea7cd81f5e9e5
+
+	if got := Hash(MustParse("name eq 'John' and age gt 18")); got != expected {
+		t.Fatalf("unexpected hash. expected=%#x got=%#x", expected, got)
+	}
+}
+
+func TestHashAsMapKey(t *testing.T) {
+	t.Parallel()
+
+	seen := map[uint64]Expression{}
+	for _, input := range []string{"a eq 1 and b eq 2", "(a eq 1) and (b eq 2)", "a eq 1 or b eq 2"} {
+		seen[Hash(MustParse(input))] = MustParse(input)
+	}
+
+	if len(seen) != 2 {
+		t.Fatalf("expected 2 distinct filters, got %d", len(seen))
+	}
+}
@@ -1,6 +1,7 @@
 package goqrius
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -15,8 +16,104 @@ var (
 const (
 	LeftSideMustBeIdentifier       = "left side of comparison must be an identifier"
 	NullCannotBeUsedWithComparison = "'null' can not be used with comparison operator"
+	IdentifierCannotBeUsedAsValue  = "identifier can not be used as value"
+	NotCannotBeAppliedToValue      = "'not' can not be applied to a value"
+	MissingExpressionAfterNot      = "missing expression after not"
+	GroupedValueIsNotAnExpression  = "grouped value is not a valid expression"
+	InvalidValueExpression         = "invalid value expression"
+	RightSideMustBeValue           = "right side of comparison must be a value"
 )
 
+// ErrorCode is a stable, machine-readable identifier of a parse error.
+type ErrorCode string
+
+const (
+	CodeIllegalToken             ErrorCode = "illegal_token"
+	CodeUnexpectedToken          ErrorCode = "unexpected_token"
+	CodeExpectedToken            ErrorCode = "expected_token"
+	CodeExpectedOperator         ErrorCode = "expected_operator"
+	CodeStandaloneValue          ErrorCode = "standalone_value"
+	CodeLeftSideMustBeIdentifier ErrorCode = "left_side_must_be_identifier"
+	CodeRightSideMustBeValue     ErrorCode = "right_side_must_be_value"
+	CodeIdentifierAsValue        ErrorCode = "identifier_as_value"
+	CodeInvalidValue             ErrorCode = "invalid_value"
+	CodeNullWithComparison       ErrorCode = "null_with_comparison"
+	CodeNotAppliedToValue        ErrorCode = "not_applied_to_value"
+	CodeMissingExpression        ErrorCode = "missing_expression"
+	CodeGroupedValue             ErrorCode = "grouped_value"
+	CodeUnknownField             ErrorCode = "unknown_field"
+	CodeTooManyErrors            ErrorCode = "too_many_errors"
+	CodeIntegerOutOfRange        ErrorCode = "integer_out_of_range"
+	CodeInvalidFilter            ErrorCode = "invalid_filter"
+	CodeTypeMismatch             ErrorCode = "type_mismatch"
+	CodeOperatorNotAllowed       ErrorCode = "operator_not_allowed"
+	CodeFieldNotAllowed          ErrorCode = "field_not_allowed"
+	CodeInputTooLong             ErrorCode = "input_too_long"
+	CodeMaxDepthExceeded         ErrorCode = "max_depth_exceeded"
+	CodeTooManyNodes             ErrorCode = "too_many_nodes"
+	CodeTooManyOrBranches        ErrorCode = "too_many_or_branches"
+	CodeTooManyFields            ErrorCode = "too_many_fields"
+)
+
+// Sentinel errors, one per ErrorCode, to be used with errors.Is.
+var (
+	ErrIllegalToken             = errors.New("illegal token")
+	ErrUnexpectedToken          = errors.New("unexpected token")
+	ErrExpectedToken            = errors.New("expected token")
+	ErrExpectedOperator         = errors.New("expected operator")
+	ErrStandaloneValue          = errors.New("standalone value")
+	ErrLeftSideMustBeIdentifier = errors.New(LeftSideMustBeIdentifier)
+	ErrRightSideMustBeValue     = errors.New(RightSideMustBeValue)
+	ErrIdentifierAsValue        = errors.New(IdentifierCannotBeUsedAsValue)
+	ErrInvalidValue             = errors.New("invalid value")
+	ErrNullWithComparison       = errors.New(NullCannotBeUsedWithComparison)
+	ErrNotAppliedToValue        = errors.New(NotCannotBeAppliedToValue)
+	ErrMissingExpression        = errors.New("missing expression")
+	ErrGroupedValue             = errors.New(GroupedValueIsNotAnExpression)
+	ErrUnknownField             = errors.New("unknown field")
+	ErrTooManyErrors            = errors.New("too many errors")
+	ErrIntegerOutOfRange        = errors.New("integer out of range")
+	ErrInvalidFilter            = errors.New("invalid filter")
+	ErrTypeMismatch             = errors.New("type mismatch")
+	ErrOperatorNotAllowed       = errors.New("operator not allowed")
+	ErrFieldNotAllowed          = errors.New("field not allowed")
+	ErrInputTooLong             = errors.New("input too long")
+	ErrMaxDepthExceeded         = errors.New("max depth exceeded")
+	ErrTooManyNodes             = errors.New("too many nodes")
+	ErrTooManyOrBranches        = errors.New("too many or branches")
+	ErrTooManyFields            = errors.New("too many fields")
+)
+
+//nolint:gochecknoglobals // lookup table from code to sentinel error.
+var sentinels = map[ErrorCode]error{
+	CodeIllegalToken:             ErrIllegalToken,
+	CodeUnexpectedToken:          ErrUnexpectedToken,
+	CodeExpectedToken:            ErrExpectedToken,
+	CodeExpectedOperator:         ErrExpectedOperator,
+	CodeStandaloneValue:          ErrStandaloneValue,
+	CodeLeftSideMustBeIdentifier: ErrLeftSideMustBeIdentifier,
+	CodeRightSideMustBeValue:     ErrRightSideMustBeValue,
+	CodeIdentifierAsValue:        ErrIdentifierAsValue,
+	CodeInvalidValue:             ErrInvalidValue,
+	CodeNullWithComparison:       ErrNullWithComparison,
+	CodeNotAppliedToValue:        ErrNotAppliedToValue,
+	CodeMissingExpression:        ErrMissingExpression,
+	CodeGroupedValue:             ErrGroupedValue,
+	CodeUnknownField:             ErrUnknownField,
+	CodeTooManyErrors:            ErrTooManyErrors,
+	CodeIntegerOutOfRange:        ErrIntegerOutOfRange,
+	CodeInvalidFilter:            ErrInvalidFilter,
+	CodeTypeMismatch:             ErrTypeMismatch,
+	CodeOperatorNotAllowed:       ErrOperatorNotAllowed,
+	CodeFieldNotAllowed:          ErrFieldNotAllowed,
+	CodeInputTooLong:             ErrInputTooLong,
+	CodeMaxDepthExceeded:         ErrMaxDepthExceeded,
+	CodeTooManyNodes:             ErrTooManyNodes,
+	CodeTooManyOrBranches:        ErrTooManyOrBranches,
+	CodeTooManyFields:            ErrTooManyFields,
+}
+
+// ParseError groups all the errors found while parsing a filter expression.
 type ParseError struct {
 	errors []error
 }
@@ -30,13 +127,29 @@ func (p ParseError) Error() string {
 	return strings.Join(errorsMessage, ",")
 }
 
+// Errors returns the individual errors found while parsing.
+func (p ParseError) Errors() []error { return p.errors }
+
+// Unwrap allows errors.Is and errors.As to inspect the individual errors.
+func (p ParseError) Unwrap() []error { return p.errors }
+
 type (
 	UnexpectedTokenError struct {
 		Token   token.Token
+		Code    ErrorCode
 		Message string
+		// Suggestions holds the closest keywords or fields, in case of a misspelling.
+		Suggestions []string
 	}
 )
 
 func (e UnexpectedTokenError) Error() string {
 	return fmt.Sprintf("%s, at position %d", e.Message, e.Token.Position)
 }
+
+// Is reports whether target is the sentinel error of the error's Code.
+func (e UnexpectedTokenError) Is(target error) bool {
+	sentinel, ok := sentinels[e.Code]
+
+	return ok && sentinel == target
+}
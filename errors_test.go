@@ -0,0 +1,78 @@
+package goqrius
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseErrorCodes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		expectedCodes []ErrorCode
+		expectedIs    []error
+	}{
+		"@ eq 1": {
+			expectedCodes: []ErrorCode{CodeIllegalToken},
+			expectedIs:    []error{ErrIllegalToken},
+		},
+		"1 gt 2": {
+			expectedCodes: []ErrorCode{CodeLeftSideMustBeIdentifier},
+			expectedIs:    []error{ErrLeftSideMustBeIdentifier},
+		},
+		"null eq name": {
+			expectedCodes: []ErrorCode{CodeLeftSideMustBeIdentifier, CodeIdentifierAsValue},
+			expectedIs:    []error{ErrLeftSideMustBeIdentifier, ErrIdentifierAsValue},
+		},
+		"name gt null": {
+			expectedCodes: []ErrorCode{CodeNullWithComparison},
+			expectedIs:    []error{ErrNullWithComparison},
+		},
+		"not true": {
+			expectedCodes: []ErrorCode{CodeNotAppliedToValue},
+			expectedIs:    []error{ErrNotAppliedToValue},
+		},
+		"(name eq 'John'": {
+			expectedCodes: []ErrorCode{CodeExpectedToken},
+			expectedIs:    []error{ErrExpectedToken},
+		},
+	}
+
+	for input, tt := range tests {
+		t.Run(input, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(input)
+
+			var pe ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("expected ParseError, got %T", err)
+			}
+
+			if len(pe.Errors()) != len(tt.expectedCodes) {
+				t.Fatalf("expected %d errors, got %d", len(tt.expectedCodes), len(pe.Errors()))
+			}
+
+			for i, e := range pe.Errors() {
+				var ute UnexpectedTokenError
+				if !errors.As(e, &ute) {
+					t.Fatalf("expected UnexpectedTokenError at [%d], got %T", i, e)
+				}
+
+				if ute.Code != tt.expectedCodes[i] {
+					t.Fatalf("expected code at [%d] to be %q, got %q", i, tt.expectedCodes[i], ute.Code)
+				}
+			}
+
+			for _, sentinel := range tt.expectedIs {
+				if !errors.Is(err, sentinel) {
+					t.Fatalf("expected errors.Is(err, %v) to be true", sentinel)
+				}
+			}
+
+			if errors.Is(err, ErrGroupedValue) {
+				t.Fatalf("expected errors.Is(err, %v) to be false", ErrGroupedValue)
+			}
+		})
+	}
+}
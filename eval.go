@@ -0,0 +1,394 @@
+package goqrius
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrFieldNotFound is returned when a field of the filter expression is not found in the data.
+	ErrFieldNotFound = errors.New("field not found")
+	// ErrUnsupportedExpression is returned when the expression can't be evaluated,
+	// e.g. a partial tree with BadExpr nodes, or a bare identifier.
+	ErrUnsupportedExpression = errors.New("unsupported expression")
+)
+
+type (
+	// EvalOption configures how an Expression is evaluated, see Eval.
+	EvalOption func(*evalOptions)
+
+	evalOptions struct {
+		missingAsNull bool
+		nullSemantics NullSemantics
+	}
+
+	// NullSemantics defines the result of comparing a null field with a value, see WithNullSemantics.
+	NullSemantics int
+
+	// truth is the result of a condition in the three-valued logic: false, unknown or true.
+	truth int8
+)
+
+const (
+	// TwoValued follows OData: a comparison of a null field with a value is false, except `ne` that is true,
+	// so `not age gt 18` matches a null age. This is the default.
+	TwoValued NullSemantics = iota
+	// ThreeValued follows SQL: a comparison of a null field with a value is unknown,
+	// and unknown propagates through not, and and or, with the Kleene logic, e.g. `not unknown` is unknown,
+	// `false and unknown` is false and `true or unknown` is true. An unknown result doesn't match,
+	// as in a SQL WHERE clause, so neither `age gt 18` nor `not age gt 18` match a null age.
+	//
+	// The comparisons with the null literal, `eq null` and `ne null`, are never unknown, as SQL `IS NULL` and `IS NOT NULL`.
+	ThreeValued
+)
+
+const (
+	truthFalse truth = iota
+	truthUnknown
+	truthTrue
+)
+
+// WithMissingAsNull evaluates the fields that are not found in the data as null, instead of returning ErrFieldNotFound.
+func WithMissingAsNull() EvalOption {
+	return func(o *evalOptions) {
+		o.missingAsNull = true
+	}
+}
+
+// WithNullSemantics sets how the comparisons of null fields are evaluated, TwoValued by default.
+// Translators to other languages, e.g. SQL, should follow the same semantics, to return the same results.
+func WithNullSemantics(semantics NullSemantics) EvalOption {
+	return func(o *evalOptions) {
+		o.nullSemantics = semantics
+	}
+}
+
+func (s NullSemantics) String() string {
+	switch s {
+	case TwoValued:
+		return "two-valued"
+	case ThreeValued:
+		return "three-valued"
+	default:
+		return fmt.Sprintf("NullSemantics(%d)", int(s))
+	}
+}
+
+// nullComparison returns the result of comparing a null field with a value that is not the null literal.
+func (o evalOptions) nullComparison(operator FilterOperator) truth {
+	if o.nullSemantics == ThreeValued {
+		return truthUnknown
+	}
+
+	return toTruth(operator == NotEq)
+}
+
+func toTruth(b bool) truth {
+	if b {
+		return truthTrue
+	}
+
+	return truthFalse
+}
+
+// and, or and not follow the Kleene logic, that is the boolean logic when there is no unknown.
+func (t truth) and(other truth) truth { return min(t, other) }
+func (t truth) or(other truth) truth  { return max(t, other) }
+func (t truth) not() truth            { return truthTrue - t }
+
+func newEvalOptions(opts ...EvalOption) evalOptions {
+	var o evalOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// Eval reports whether data matches expr, following the OData comparison semantics:
+//   - integers are compared numerically with any Go number, including the float64 of decoded JSON.
+//   - strings are compared by their bytes, and with time.Time values when the string is an RFC 3339 date.
+//   - booleans can only be compared with eq and ne.
+//   - null is only equal to null, and the comparisons of a null field with a value are false, except ne,
+//     see WithNullSemantics for the SQL semantics.
+//
+// A dotted identifier, e.g. `address.city`, reads the nested maps of data.
+// A nil expr, the result of parsing an empty filter, matches any data.
+func Eval(expr Expression, data map[string]any, opts ...EvalOption) (bool, error) {
+	e := evaluator{options: newEvalOptions(opts...), resolve: mapResolver(data)}
+
+	t, err := e.eval(expr)
+
+	return t == truthTrue, err
+}
+
+// resolver returns the value of the field in path, and whether it was found.
+type resolver func(path string) (any, bool)
+
+func mapResolver(data map[string]any) resolver {
+	return func(path string) (any, bool) {
+		var current any = data
+
+		for segment := range strings.SplitSeq(path, ".") {
+			m, ok := current.(map[string]any)
+			if !ok {
+				if current == nil {
+					// the navigation through a null is null.
+					return nil, true
+				}
+
+				return nil, false
+			}
+
+			current, ok = m[segment]
+			if !ok {
+				return nil, false
+			}
+		}
+
+		return current, true
+	}
+}
+
+type evaluator struct {
+	options evalOptions
+	resolve resolver
+}
+
+func (e *evaluator) eval(expr Expression) (truth, error) {
+	switch x := expr.(type) {
+	case nil:
+		return truthTrue, nil
+	case *AndExpr:
+		left, err := e.eval(x.Left)
+		if err != nil || left == truthFalse {
+			return truthFalse, err
+		}
+
+		right, err := e.eval(x.Right)
+		if err != nil {
+			return truthFalse, err
+		}
+
+		return left.and(right), nil
+	case *OrExpr:
+		left, err := e.eval(x.Left)
+		if err != nil || left == truthTrue {
+			return left, err
+		}
+
+		right, err := e.eval(x.Right)
+		if err != nil {
+			return truthFalse, err
+		}
+
+		return left.or(right), nil
+	case *NotExpr:
+		right, err := e.eval(x.Right)
+		if err != nil {
+			return truthFalse, err
+		}
+
+		return right.not(), nil
+	case *FilterExpr:
+		return e.evalFilter(x)
+	default:
+		return truthFalse, fmt.Errorf("%w: %s at position %d", ErrUnsupportedExpression, expr, expr.Pos())
+	}
+}
+
+func (e *evaluator) evalFilter(f *FilterExpr) (truth, error) {
+	if f.Left == nil || f.Right == nil {
+		return truthFalse, fmt.Errorf("%w: incomplete comparison at position %d", ErrUnsupportedExpression, f.Pos())
+	}
+
+	actual, found := e.resolve(f.Left.Value)
+	if !found {
+		if !e.options.missingAsNull {
+			return truthFalse, fmt.Errorf("%w: %q at position %d", ErrFieldNotFound, f.Left.Value, f.Left.Pos())
+		}
+
+		actual = nil
+	}
+
+	actual = normalize(actual)
+
+	switch f.Right.(type) {
+	case *Null, *BadExpr, *MissingExpr:
+	default:
+		if actual == nil {
+			return e.options.nullComparison(f.Operator), nil
+		}
+	}
+
+	ok, err := compareValue(f.Operator, actual, f.Right)
+	if err != nil {
+		return truthFalse, fmt.Errorf("%q %s %s at position %d: %w", f.Left.Value, f.Operator, f.Right, f.Pos(), err)
+	}
+
+	return toTruth(ok), nil
+}
+
+// compareValue compares the normalized actual value of a field with the literal of the filter expression.
+func compareValue(operator FilterOperator, actual any, literal Value) (bool, error) {
+	switch literal.(type) {
+	case *Null:
+		return applyEquality(operator, actual == nil)
+	case *BadExpr, *MissingExpr:
+		return false, fmt.Errorf("%w: %s", ErrUnsupportedExpression, literal)
+	}
+
+	if actual == nil {
+		// any comparison with null, except ne, is false.
+		return operator == NotEq, nil
+	}
+
+	switch lit := literal.(type) {
+	case *IntegerLiteral:
+		c, err := compareNumber(actual, lit)
+		if err != nil {
+			return false, err
+		}
+
+		return applyOperator(operator, c), nil
+	case *StringLiteral:
+		switch a := actual.(type) {
+		case string:
+			return applyOperator(operator, strings.Compare(a, lit.Value)), nil
+		case time.Time:
+			t, err := lit.Time()
+			if err != nil {
+				return false, fmt.Errorf("%w: %q is not a date to compare with a time", ErrTypeMismatch, lit.Value)
+			}
+
+			return applyOperator(operator, a.Compare(t)), nil
+		}
+	case *BooleanLiteral:
+		if a, ok := actual.(bool); ok {
+			return applyEquality(operator, a == lit.Value)
+		}
+	}
+
+	return false, fmt.Errorf("%w: can not compare %T with %s", ErrTypeMismatch, actual, literal)
+}
+
+// applyEquality returns the result of the eq or ne operator, for values that can't be ordered.
+func applyEquality(operator FilterOperator, equal bool) (bool, error) {
+	switch operator {
+	case Eq:
+		return equal, nil
+	case NotEq:
+		return !equal, nil
+	default:
+		return false, fmt.Errorf("%w: operator %s can not be applied to the value", ErrTypeMismatch, operator)
+	}
+}
+
+// applyOperator returns the result of the operator, given the comparison c of both sides.
+func applyOperator(operator FilterOperator, c int) bool {
+	switch operator {
+	case Eq:
+		return c == 0
+	case NotEq:
+		return c != 0
+	case GreaterThan:
+		return c > 0
+	case GreaterThanOrEqual:
+		return c >= 0
+	case LessThan:
+		return c < 0
+	case LessThanOrEqual:
+		return c <= 0
+	default:
+		return false
+	}
+}
+
+// compareNumber compares a normalized number with the integer literal, without losing precision.
+func compareNumber(actual any, lit *IntegerLiteral) (int, error) {
+	if a, ok := actual.(int64); ok {
+		if i, err := lit.Int64(); err == nil {
+			return cmp.Compare(a, i), nil
+		}
+	}
+
+	r, ok := new(big.Rat).SetString(lit.Value)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q is not an integer", ErrInvalidValue, lit.Value)
+	}
+
+	switch a := actual.(type) {
+	case int64:
+		return new(big.Rat).SetInt64(a).Cmp(r), nil
+	case uint64:
+		return new(big.Rat).SetUint64(a).Cmp(r), nil
+	case *big.Int:
+		return new(big.Rat).SetInt(a).Cmp(r), nil
+	case float64:
+		switch {
+		case math.IsNaN(a):
+			return 0, fmt.Errorf("%w: NaN can not be compared", ErrTypeMismatch)
+		case math.IsInf(a, 0):
+			return int(math.Copysign(1, a)), nil
+		}
+
+		return new(big.Rat).SetFloat64(a).Cmp(r), nil
+	case json.Number:
+		n, isNumber := new(big.Rat).SetString(a.String())
+		if !isNumber {
+			return 0, fmt.Errorf("%w: %q is not a number", ErrTypeMismatch, a)
+		}
+
+		return n.Cmp(r), nil
+	default:
+		return 0, fmt.Errorf("%w: can not compare %T with an integer", ErrTypeMismatch, actual)
+	}
+}
+
+// normalize converts the value of a field to one of nil, bool, string, int64, uint64, float64, json.Number,
+// *big.Int or time.Time, following pointers and using the underlying type of named types.
+// Other types are returned as they are, and fail to compare.
+func normalize(v any) any {
+	switch x := v.(type) {
+	case nil, bool, string, int64, uint64, float64, json.Number, time.Time:
+		return v
+	case int:
+		return int64(x)
+	case *big.Int:
+		if x == nil {
+			return nil
+		}
+
+		return x
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+
+		return normalize(rv.Elem().Interface())
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.String:
+		return rv.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	default:
+		return v
+	}
+}
@@ -0,0 +1,224 @@
+package goqrius
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEval(t *testing.T) {
+	t.Parallel()
+
+	type status string
+
+	age := 20
+
+	data := map[string]any{
+		"name":    "John",
+		"surname": "Doe",
+		"age":     &age,
+		"score":   float64(7.5),
+		"balance": int8(-5),
+		"views":   uint64(18446744073709551615),
+		"count":   json.Number("42"),
+		"active":  true,
+		"status":  status("active"),
+		"email":   nil,
+		"created": time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC),
+		"address": map[string]any{
+			"city": "Madrid",
+			"geo":  nil,
+		},
+	}
+
+	tests := map[string]struct {
+		filter   string
+		expected bool
+	}{
+		"string eq":               {filter: "name eq 'John'", expected: true},
+		"string ne":               {filter: "name ne 'John'", expected: false},
+		"string gt":               {filter: "name gt 'Jane'", expected: true},
+		"int gt":                  {filter: "age gt 18", expected: true},
+		"int le":                  {filter: "age le 18", expected: false},
+		"float compared with int": {filter: "score gt 7 and score lt 8", expected: true},
+		"int8 value":              {filter: "balance lt 0", expected: true},
+		"big unsigned":            {filter: "views gt 9223372036854775807", expected: true},
+		"literal out of int64":    {filter: "age lt 99999999999999999999", expected: true},
+		"json number":             {filter: "count eq 42", expected: true},
+		"boolean":                 {filter: "active eq true", expected: true},
+		"boolean ne":              {filter: "active ne true", expected: false},
+		"named type":              {filter: "status eq 'active'", expected: true},
+		"null eq null":            {filter: "email eq null", expected: true},
+		"null ne null":            {filter: "email ne null", expected: false},
+		"value eq null":           {filter: "name eq null", expected: false},
+		"null gt value":           {filter: "email gt 'a'", expected: false},
+		"null ne value":           {filter: "email ne 'a'", expected: true},
+		"time":                    {filter: "created gt '2025-01-01T00:00:00Z'", expected: true},
+		"nested field":            {filter: "address.city eq 'Madrid'", expected: true},
+		"through null":            {filter: "address.geo.lat eq null", expected: true},
+		"and":                     {filter: "name eq 'John' and age lt 18", expected: false},
+		"or":                      {filter: "name eq 'Jane' or age gt 18", expected: true},
+		"not":                     {filter: "not name eq 'Jane'", expected: true},
+		"short circuit of and":    {filter: "name eq 'Jane' and unknown eq 1", expected: false},
+		"short circuit of or":     {filter: "name eq 'John' or unknown eq 1", expected: true},
+		"precedence":              {filter: "name eq 'Jane' and age gt 18 or surname eq 'Doe'", expected: true},
+		"parentheses":             {filter: "name eq 'Jane' and (age gt 18 or surname eq 'Doe')", expected: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Eval(MustParse(tt.filter), data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.expected {
+				t.Fatalf("unexpected result for %q. expected=%t got=%t", tt.filter, tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{
+		"name":    "John",
+		"age":     20,
+		"active":  true,
+		"created": time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC),
+		"address": map[string]any{"city": "Madrid"},
+	}
+
+	tests := map[string]struct {
+		expr     Expression
+		expected error
+	}{
+		"missing field":         {expr: MustParse("surname eq 'Doe'"), expected: ErrFieldNotFound},
+		"missing nested field":  {expr: MustParse("address.zip eq 1"), expected: ErrFieldNotFound},
+		"field is not a map":    {expr: MustParse("name.first eq 'J'"), expected: ErrFieldNotFound},
+		"string with int":       {expr: MustParse("name eq 1"), expected: ErrTypeMismatch},
+		"int with string":       {expr: MustParse("age eq '20'"), expected: ErrTypeMismatch},
+		"ordered booleans":      {expr: MustParse("active gt false"), expected: ErrTypeMismatch},
+		"time with non date":    {expr: MustParse("created gt 'yesterday'"), expected: ErrTypeMismatch},
+		"map compared":          {expr: MustParse("address eq 'Madrid'"), expected: ErrTypeMismatch},
+		"partial tree":          {expr: &AndExpr{Left: MustParse("age gt 1"), Right: &BadExpr{}}, expected: ErrUnsupportedExpression},
+		"missing value":         {expr: &FilterExpr{Left: &Identifier{Value: "age"}, Operator: Eq, Right: &MissingExpr{}}, expected: ErrUnsupportedExpression},
+		"identifier as operand": {expr: &NotExpr{Right: &Identifier{Value: "active"}}, expected: ErrUnsupportedExpression},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Eval(tt.expr, data); !errors.Is(err, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestEvalWithMissingAsNull(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{"name": "John"}
+
+	got, err := Eval(MustParse("email eq null and name eq 'John'"), data, WithMissingAsNull())
+	if err != nil || !got {
+		t.Fatalf("expected a match, got %t, %v", got, err)
+	}
+}
+
+func TestEvalNilExpression(t *testing.T) {
+	t.Parallel()
+
+	got, err := Eval(nil, map[string]any{})
+	if err != nil || !got {
+		t.Fatalf("expected a nil expression to match, got %t, %v", got, err)
+	}
+}
+
+func TestEvalWithNullSemantics(t *testing.T) {
+	t.Parallel()
+
+	type user struct {
+		Name  string  `json:"name"`
+		Email *string `json:"email"`
+	}
+
+	data := map[string]any{"name": "John", "email": nil}
+	value := user{Name: "John"}
+
+	tests := map[string]struct {
+		filter      string
+		twoValued   bool
+		threeValued bool
+	}{
+		"null gt value":     {filter: "email gt 'a'", twoValued: false, threeValued: false},
+		"null ne value":     {filter: "email ne 'a'", twoValued: true, threeValued: false},
+		"not of unknown":    {filter: "not email eq 'a'", twoValued: true, threeValued: false},
+		"false and unknown": {filter: "name eq 'Jane' and email eq 'a'", twoValued: false, threeValued: false},
+		"true and unknown":  {filter: "name eq 'John' and not email eq 'a'", twoValued: true, threeValued: false},
+		"true or unknown":   {filter: "email eq 'a' or name eq 'John'", twoValued: true, threeValued: true},
+		"not of false or unknown": {
+			filter: "not (name eq 'Jane' or email eq 'a')", twoValued: true, threeValued: false,
+		},
+		"eq null":     {filter: "email eq null", twoValued: true, threeValued: true},
+		"not ne null": {filter: "not email ne null", twoValued: true, threeValued: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr := MustParse(tt.filter)
+
+			for semantics, expected := range map[NullSemantics]bool{TwoValued: tt.twoValued, ThreeValued: tt.threeValued} {
+				got, err := Eval(expr, data, WithNullSemantics(semantics))
+				if err != nil || got != expected {
+					t.Fatalf("Eval %q with %s: expected %t, got %t, %v", tt.filter, semantics, expected, got, err)
+				}
+
+				got, err = Match(expr, value, WithNullSemantics(semantics))
+				if err != nil || got != expected {
+					t.Fatalf("Match %q with %s: expected %t, got %t, %v", tt.filter, semantics, expected, got, err)
+				}
+
+				predicate, err := Compile[user](expr, WithNullSemantics(semantics))
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if got = predicate(value); got != expected {
+					t.Fatalf("Compile %q with %s: expected %t, got %t", tt.filter, semantics, expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestNullSemanticsString(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		semantics NullSemantics
+		expected  string
+	}{
+		"two-valued":   {semantics: TwoValued, expected: "two-valued"},
+		"three-valued": {semantics: ThreeValued, expected: "three-valued"},
+		"unknown":      {semantics: NullSemantics(7), expected: "NullSemantics(7)"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.semantics.String(); got != tt.expected {
+				t.Fatalf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
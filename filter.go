@@ -0,0 +1,41 @@
+package goqrius
+
+import (
+	"iter"
+)
+
+// Filter returns the items matching expr, in the same order, see Compile.
+// The items are not modified, and the returned slice is a new one.
+func Filter[T any](items []T, expr Expression, opts ...EvalOption) ([]T, error) {
+	predicate, err := Compile[T](expr, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []T
+
+	for _, item := range items {
+		if predicate(item) {
+			filtered = append(filtered, item)
+		}
+	}
+
+	return filtered, nil
+}
+
+// FilterSeq returns a sequence with the items of seq matching expr, see Compile.
+// The items are filtered lazily, while iterating the returned sequence.
+func FilterSeq[T any](seq iter.Seq[T], expr Expression, opts ...EvalOption) (iter.Seq[T], error) {
+	predicate, err := Compile[T](expr, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(yield func(T) bool) {
+		for item := range seq {
+			if predicate(item) && !yield(item) {
+				return
+			}
+		}
+	}, nil
+}
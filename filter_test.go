@@ -0,0 +1,101 @@
+package goqrius
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	users := compileUsers()
+
+	tests := map[string]struct {
+		filter   string
+		expected []string
+	}{
+		"some match": {
+			filter:   "age gt 18",
+			expected: []string{"John", "Alice"},
+		},
+		"none match": {
+			filter:   "name eq 'Bob'",
+			expected: nil,
+		},
+		"all match": {
+			filter:   "",
+			expected: []string{"John", "Jane", "Alice"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, _ := Parse(tt.filter)
+
+			filtered, err := Filter(users, expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := userNames(filtered); !slices.Equal(got, tt.expected) {
+				t.Fatalf("unexpected users. expected=%v got=%v", tt.expected, got)
+			}
+
+			seq, err := FilterSeq(slices.Values(users), expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := userNames(slices.Collect(seq)); !slices.Equal(got, tt.expected) {
+				t.Fatalf("unexpected users of the sequence. expected=%v got=%v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFilterError(t *testing.T) {
+	t.Parallel()
+
+	expr := MustParse("surname eq 'Doe'")
+
+	if _, err := Filter(compileUsers(), expr); !errors.Is(err, ErrFieldNotFound) {
+		t.Fatalf("expected ErrFieldNotFound, got %v", err)
+	}
+
+	if seq, err := FilterSeq(slices.Values(compileUsers()), expr); seq != nil || !errors.Is(err, ErrFieldNotFound) {
+		t.Fatalf("expected ErrFieldNotFound, got %v", err)
+	}
+}
+
+func TestFilterSeqStops(t *testing.T) {
+	t.Parallel()
+
+	seq, err := FilterSeq(slices.Values(compileUsers()), MustParse("age gt 0"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+
+	for user := range seq {
+		got = append(got, user.Name)
+
+		break
+	}
+
+	if !slices.Equal(got, []string{"John"}) {
+		t.Fatalf("unexpected users %v", got)
+	}
+}
+
+func userNames(users []compileUser) []string {
+	var names []string
+	for _, user := range users {
+		names = append(names, user.Name)
+	}
+
+	return names
+}
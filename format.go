@@ -0,0 +1,262 @@
+package goqrius
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+type (
+	// FormatOption configures how Format prints an Expression.
+	FormatOption func(*formatter)
+
+	formatter struct {
+		indent       string
+		sortOperands bool
+		sb           strings.Builder
+	}
+)
+
+// WithIndent prints every and/or operand in its own line, and the content of the parentheses
+// indented with indent, e.g. with "\t":
+//
+//	name eq 'John'
+//	or (
+//		age gt 18
+//		and age lt 65
+//	)
+func WithIndent(indent string) FormatOption {
+	return func(f *formatter) {
+		f.indent = indent
+	}
+}
+
+// WithSortedOperands sorts the operands of the and/or expressions, that are commutative,
+// so expressions that only differ in the order of their operands are printed the same way,
+// e.g. `b eq 2 and (a eq 1 and c eq 3)` is printed as `a eq 1 and b eq 2 and c eq 3`.
+// Notice that the result is then not Equal to expr, but it is equivalent.
+func WithSortedOperands() FormatOption {
+	return func(f *formatter) {
+		f.sortOperands = true
+	}
+}
+
+// Canonicalize parses input, accepting the keywords in any casing, and prints it in its canonical form:
+// lowercase keywords, single spaces, single quoted strings, minimal parentheses and sorted and/or operands.
+// The result is stable, so it can be used to store or diff filter expressions.
+func Canonicalize(input string) (string, error) {
+	expr, err := Parse(input, WithCaseInsensitiveKeywords())
+	if err != nil {
+		return "", err
+	}
+
+	return Format(expr, WithSortedOperands()), nil
+}
+
+// Format prints expr as a filter expression, with the minimal parentheses needed to keep its structure,
+// so Parse(Format(expr)) is Equal to expr.
+// String literals are quoted, escaping every single quote by doubling it.
+func Format(expr Expression, opts ...FormatOption) string {
+	f := &formatter{}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	if f.sortOperands {
+		expr = sortOperands(expr)
+	}
+
+	if expr != nil {
+		f.format(expr, 0)
+	}
+
+	return f.sb.String()
+}
+
+// sortOperands returns a copy of expr where the chains of and/or expressions are flattened,
+// sorted by their formatted text and rebuilt left associative.
+func sortOperands(expr Expression) Expression {
+	if isNilNode(expr) {
+		return expr
+	}
+
+	switch e := expr.(type) {
+	case *AndExpr:
+		operands := sortedOperands(e)
+
+		return rebuild(operands, func(left, right Expression) Expression { return &AndExpr{Left: left, Right: right} })
+	case *OrExpr:
+		operands := sortedOperands(e)
+
+		return rebuild(operands, func(left, right Expression) Expression { return &OrExpr{Left: left, Right: right} })
+	case *NotExpr:
+		return &NotExpr{Right: sortOperands(e.Right), Span: e.Span}
+	default:
+		return expr
+	}
+}
+
+// sortedOperands returns the operands of the chain of expressions of the same type as expr, sorted.
+func sortedOperands(expr Expression) []Expression {
+	var operands []Expression
+
+	var flatten func(Expression)
+	flatten = func(operand Expression) {
+		if isNilNode(operand) {
+			operands = append(operands, operand)
+
+			return
+		}
+
+		switch o := operand.(type) {
+		case *AndExpr:
+			if _, isAnd := expr.(*AndExpr); isAnd {
+				flatten(o.Left)
+				flatten(o.Right)
+
+				return
+			}
+		case *OrExpr:
+			if _, isOr := expr.(*OrExpr); isOr {
+				flatten(o.Left)
+				flatten(o.Right)
+
+				return
+			}
+		}
+
+		operands = append(operands, sortOperands(operand))
+	}
+	flatten(expr)
+
+	keys := make(map[Expression]string, len(operands))
+	for _, operand := range operands {
+		keys[operand] = Format(operand)
+	}
+
+	slices.SortStableFunc(operands, func(a, b Expression) int {
+		return strings.Compare(keys[a], keys[b])
+	})
+
+	return operands
+}
+
+func rebuild(operands []Expression, join func(left, right Expression) Expression) Expression {
+	result := operands[0]
+	for _, operand := range operands[1:] {
+		result = join(result, operand)
+	}
+
+	return result
+}
+
+func (f *formatter) format(expr Expression, level int) {
+	if isNilNode(expr) {
+		// a node missing in a built tree, e.g. a FilterExpr without Left, is printed as a MissingExpr.
+		f.sb.WriteString((&MissingExpr{}).String())
+
+		return
+	}
+
+	switch e := expr.(type) {
+	case *AndExpr:
+		f.formatBinary(e.Left, e.Right, "and", and, level)
+	case *OrExpr:
+		f.formatBinary(e.Left, e.Right, "or", or, level)
+	case *NotExpr:
+		f.sb.WriteString("not ")
+		f.formatOperand(e.Right, precedenceOf(e.Right) < prefix, level)
+	case *FilterExpr:
+		if e.Left == nil {
+			f.format(nil, level)
+		} else {
+			f.format(e.Left, level)
+		}
+
+		f.sb.WriteString(" " + string(e.Operator) + " ")
+		f.format(e.Right, level)
+	case *StringLiteral:
+		f.sb.WriteString(quote(e.Value))
+	default:
+		f.sb.WriteString(e.String())
+	}
+}
+
+// formatBinary prints a left associative and/or expression.
+func (f *formatter) formatBinary(left, right Expression, operator string, precedence, level int) {
+	f.formatOperand(left, precedenceOf(left) < precedence, operandLevel(left, precedence, level))
+
+	if f.indent != "" {
+		f.newLine(level)
+	} else {
+		f.sb.WriteString(" ")
+	}
+
+	f.sb.WriteString(operator + " ")
+	f.formatOperand(right, precedenceOf(right) <= precedence, operandLevel(right, precedence, level))
+}
+
+// operandLevel indents one more level an and operand of an or expression, so the lines of the and are nested.
+func operandLevel(operand Expression, precedence, level int) int {
+	if _, isAnd := operand.(*AndExpr); isAnd && precedence == or {
+		return level + 1
+	}
+
+	return level
+}
+
+func (f *formatter) formatOperand(expr Expression, parenthesize bool, level int) {
+	if !parenthesize {
+		f.format(expr, level)
+
+		return
+	}
+
+	f.sb.WriteString("(")
+
+	if f.indent != "" {
+		f.newLine(level + 1)
+		f.format(expr, level+1)
+		f.newLine(level)
+	} else {
+		f.format(expr, level)
+	}
+
+	f.sb.WriteString(")")
+}
+
+func (f *formatter) newLine(level int) {
+	f.sb.WriteString("\n" + strings.Repeat(f.indent, level))
+}
+
+// precedenceOf returns how tight the expression binds, using the parser precedences.
+func precedenceOf(expr Expression) int {
+	switch expr.(type) {
+	case *OrExpr:
+		return or
+	case *AndExpr:
+		return and
+	case *NotExpr:
+		return prefix
+	case *FilterExpr:
+		return compare
+	default:
+		return compare + 1
+	}
+}
+
+// isNilNode reports whether expr is nil, or a nil pointer to a node.
+func isNilNode(expr Expression) bool {
+	if expr == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(expr)
+
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+// quote returns s as a string literal, escaping the single quotes.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,256 @@
+package goqrius
+
+import (
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input    string
+		expected string
+	}{
+		"simple filter": {
+			input:    "name eq 'John'",
+			expected: "name eq 'John'",
+		},
+		"redundant parentheses are removed": {
+			input:    "((name eq 'John') and (age gt 18))",
+			expected: "name eq 'John' and age gt 18",
+		},
+		"or inside and keeps parentheses": {
+			input:    "name eq 'John' and (age lt 18 or age gt 65)",
+			expected: "name eq 'John' and (age lt 18 or age gt 65)",
+		},
+		"and inside or has no parentheses": {
+			input:    "name eq 'John' or (age gt 18 and age lt 65)",
+			expected: "name eq 'John' or age gt 18 and age lt 65",
+		},
+		"left associativity": {
+			input:    "(a eq 1 and b eq 2) and c eq 3",
+			expected: "a eq 1 and b eq 2 and c eq 3",
+		},
+		"right nested keeps parentheses": {
+			input:    "a eq 1 and (b eq 2 and c eq 3)",
+			expected: "a eq 1 and (b eq 2 and c eq 3)",
+		},
+		"not with comparison": {
+			input:    "not (name eq 'John')",
+			expected: "not name eq 'John'",
+		},
+		"not with logical operator": {
+			input:    "not (name eq 'John' or age gt 18)",
+			expected: "not (name eq 'John' or age gt 18)",
+		},
+		"double not": {
+			input:    "not not name eq 'John'",
+			expected: "not not name eq 'John'",
+		},
+		"escaped string": {
+			input:    "name eq 'O''Neil'",
+			expected: "name eq 'O''Neil'",
+		},
+		"null": {
+			input:    "email ne null",
+			expected: "email ne null",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr := MustParse(tt.input)
+
+			got := Format(expr)
+			if got != tt.expected {
+				t.Fatalf("unexpected format. expected=%q got=%q", tt.expected, got)
+			}
+
+			if !Equal(MustParse(got), expr) {
+				t.Fatalf("formatted expression is not equal to the original. expected=%s got=%s", expr, MustParse(got))
+			}
+		})
+	}
+}
+
+func TestFormatBuiltExpression(t *testing.T) {
+	t.Parallel()
+
+	expr := &OrExpr{
+		Left: &AndExpr{
+			Left: &FilterExpr{Left: &Identifier{Value: "a"}, Operator: Eq, Right: &StringLiteral{Value: "it's"}},
+			Right: &OrExpr{
+				Left:  &FilterExpr{Left: &Identifier{Value: "b"}, Operator: Eq, Right: &Null{}},
+				Right: &NotExpr{Right: &FilterExpr{Left: &Identifier{Value: "c"}, Operator: LessThan, Right: &IntegerLiteral{Value: "1"}}},
+			},
+		},
+		Right: &OrExpr{
+			Left:  &FilterExpr{Left: &Identifier{Value: "d"}, Operator: GreaterThan, Right: &IntegerLiteral{Value: "2"}},
+			Right: &FilterExpr{Left: &Identifier{Value: "e"}, Operator: NotEq, Right: &IntegerLiteral{Value: "3"}},
+		},
+	}
+
+	expected := "a eq 'it''s' and (b eq null or not c lt 1) or (d gt 2 or e ne 3)"
+	if got := Format(expr); got != expected {
+		t.Fatalf("unexpected format. expected=%q got=%q", expected, got)
+	}
+
+	if !Equal(MustParse(expected), expr) {
+		t.Fatalf("formatted expression is not equal to the original")
+	}
+}
+
+func TestFormatMissingNodes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		expr     Expression
+		expected string
+	}{
+		"filter without left":  {expr: &FilterExpr{Operator: Eq, Right: &Null{}}, expected: "<missing> eq null"},
+		"filter without right": {expr: &FilterExpr{Left: &Identifier{Value: "a"}, Operator: Eq}, expected: "a eq <missing>"},
+		"nil value":            {expr: &FilterExpr{Left: &Identifier{Value: "a"}, Operator: Eq, Right: (*StringLiteral)(nil)}, expected: "a eq <missing>"},
+		"and without operands": {expr: &AndExpr{}, expected: "<missing> and <missing>"},
+		"not without operand":  {expr: &NotExpr{}, expected: "not <missing>"},
+		"nil node":             {expr: (*OrExpr)(nil), expected: "<missing>"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Format(tt.expr); got != tt.expected {
+				t.Fatalf("unexpected format. expected=%q got=%q", tt.expected, got)
+			}
+
+			if got := Format(tt.expr, WithSortedOperands()); got != tt.expected {
+				t.Fatalf("unexpected sorted format. expected=%q got=%q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatWithIndent(t *testing.T) {
+	t.Parallel()
+
+	expr := MustParse("name eq 'John' or (age gt 18 and age lt 65) and not (email eq null or email eq '')")
+
+	expected := `name eq 'John'
+or age gt 18
+	and age lt 65
+	and not (
+		email eq null
+		or email eq ''
+	)`
+
+	got := Format(expr, WithIndent("\t"))
+	if got != expected {
+		t.Fatalf("unexpected format.\nexpected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	if !Equal(MustParse(got), expr) {
+		t.Fatalf("formatted expression is not equal to the original")
+	}
+}
+
+func TestFormatWithSortedOperands(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input    string
+		expected string
+	}{
+		"and operands are sorted": {
+			input:    "name eq 'John' and age gt 18",
+			expected: "age gt 18 and name eq 'John'",
+		},
+		"nested chains are flattened": {
+			input:    "c eq 3 and (b eq 2 and a eq 1)",
+			expected: "a eq 1 and b eq 2 and c eq 3",
+		},
+		"or inside and": {
+			input:    "z eq 1 and (y eq 2 or x eq 3)",
+			expected: "(x eq 3 or y eq 2) and z eq 1",
+		},
+		"not operand is sorted": {
+			input:    "not (b eq 2 or a eq 1)",
+			expected: "not (a eq 1 or b eq 2)",
+		},
+		"comparison is not reordered": {
+			input:    "name eq 'John'",
+			expected: "name eq 'John'",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := Format(MustParse(tt.input), WithSortedOperands())
+			if got != tt.expected {
+				t.Fatalf("unexpected format. expected=%q got=%q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		"keyword casing": {
+			input:    "Name EQ 'John' AND Age Gt 18",
+			expected: "Age gt 18 and Name eq 'John'",
+		},
+		"spacing and parentheses": {
+			input:    "  ((a eq 1))   or   ( b  eq 2 )",
+			expected: "a eq 1 or b eq 2",
+		},
+		"same result for reordered operands": {
+			input:    "b eq 2 or a eq 1",
+			expected: "a eq 1 or b eq 2",
+		},
+		"escaped quotes are kept": {
+			input:    "name EQ 'O''Neil'",
+			expected: "name eq 'O''Neil'",
+		},
+		"null keyword": {
+			input:    "email NE NULL",
+			expected: "email ne null",
+		},
+		"invalid filter": {
+			input:   "name EQ",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Canonicalize(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.expected {
+				t.Fatalf("unexpected canonical form. expected=%q got=%q", tt.expected, got)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			again, err := Canonicalize(got)
+			if err != nil || again != got {
+				t.Fatalf("canonical form is not stable. expected=%q got=%q, err=%v", got, again, err)
+			}
+		})
+	}
+}
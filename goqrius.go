@@ -2,18 +2,35 @@
 package goqrius
 
 import (
+	"fmt"
+
 	"github.com/golaxo/goqrius/internal/lexer"
+	"github.com/golaxo/goqrius/internal/token"
 )
 
 // Parse the input filter expression to a goqrius Expression.
-func Parse(input string) (Expression, error) {
+//
+// When the input is not valid, a ParseError is returned together with a partial Expression,
+// where the parts that could not be parsed are replaced by BadExpr or MissingExpr nodes.
+// The limits of the options, e.g. WithMaxDepth, protect from abusive inputs, see the ParseOption functions.
+func Parse(input string, opts ...ParseOption) (Expression, error) {
 	if input == "" {
 		//nolint:nilnil // TODO think about returning something like EmptyExpression{}, nil.
 		return nil, nil
 	}
 
-	l := lexer.New(input)
-	p := newParser(l)
+	o := newParseOptions(opts...)
+	if o.maxLength > 0 && len(input) > o.maxLength {
+		return nil, ParseError{errors: []error{UnexpectedTokenError{
+			// the rest of the input is not echoed back, as it can be as long as the client wants.
+			Token:   token.Token{Type: token.Illegal, Position: o.maxLength, End: o.maxLength},
+			Code:    CodeInputTooLong,
+			Message: fmt.Sprintf("input longer than %d bytes", o.maxLength),
+		}}}
+	}
+
+	l := lexer.New(input, o.lexerOptions()...)
+	p := newParser(l, opts...)
 	e := p.parse()
 
 	var err error
@@ -24,8 +41,8 @@ func Parse(input string) (Expression, error) {
 	return e, err
 }
 
-func MustParse(input string) Expression {
-	e, err := Parse(input)
+func MustParse(input string, opts ...ParseOption) Expression {
+	e, err := Parse(input, opts...)
 	if err != nil {
 		panic(err)
 	}
@@ -1,6 +1,7 @@
 package lexer
 
 import (
+	"strings"
 	"unicode"
 
 	"github.com/golaxo/goqrius/internal/token"
@@ -13,11 +14,27 @@ type Lexer struct {
 	position int
 	// Current reading position in input (after current char)
 	readPosition int
+	// caseInsensitiveKeywords reads keywords in any casing, e.g. `EQ` as `eq`.
+	caseInsensitiveKeywords bool
+}
+
+// Option configures the Lexer.
+type Option func(*Lexer)
+
+// WithCaseInsensitiveKeywords reads the keywords in any casing, e.g. `AND` or `Eq`.
+func WithCaseInsensitiveKeywords() Option {
+	return func(l *Lexer) {
+		l.caseInsensitiveKeywords = true
+	}
 }
 
 // New creates a new Lexer.
-func New(input string) *Lexer {
+func New(input string, opts ...Option) *Lexer {
 	l := &Lexer{input: input}
+	for _, opt := range opts {
+		opt(l)
+	}
+
 	// Initialize positions so that getChar works correctly
 	l.position = 0
 	l.readPosition = 0
@@ -35,7 +52,7 @@ func (l *Lexer) NextToken() token.Token {
 
 	ch, ok := l.peekChar()
 	if !ok {
-		return token.Token{Type: token.EOF, Literal: "", Position: startPos}
+		return token.Token{Type: token.EOF, Literal: "", Position: startPos, End: startPos}
 	}
 
 	// Single-character tokens (delimiters)
@@ -43,31 +60,31 @@ func (l *Lexer) NextToken() token.Token {
 	case '(':
 		l.readChar()
 
-		return token.Token{Type: token.Lparen, Literal: string(token.Lparen), Position: startPos}
+		return token.Token{Type: token.Lparen, Literal: string(token.Lparen), Position: startPos, End: l.readPosition}
 	case ')':
 		l.readChar()
 
-		return token.Token{Type: token.Rparen, Literal: string(token.Rparen), Position: startPos}
+		return token.Token{Type: token.Rparen, Literal: string(token.Rparen), Position: startPos, End: l.readPosition}
 	case '{':
 		l.readChar()
 
-		return token.Token{Type: token.Lbrace, Literal: string(token.Lbrace), Position: startPos}
+		return token.Token{Type: token.Lbrace, Literal: string(token.Lbrace), Position: startPos, End: l.readPosition}
 	case '}':
 		l.readChar()
 
-		return token.Token{Type: token.Rbrace, Literal: string(token.Rbrace), Position: startPos}
+		return token.Token{Type: token.Rbrace, Literal: string(token.Rbrace), Position: startPos, End: l.readPosition}
 	case '\'':
 		// String literal
 		str := l.readSingleQuoted()
 
-		return token.Token{Type: token.String, Literal: str, Position: startPos}
+		return token.Token{Type: token.String, Literal: str, Position: startPos, End: l.readPosition}
 	}
 
 	// Numbers
 	if isDigit(ch) {
 		num := l.readWhile(isDigit)
 
-		return token.Token{Type: token.Int, Literal: num, Position: startPos}
+		return token.Token{Type: token.Int, Literal: num, Position: startPos, End: l.readPosition}
 	}
 
 	// Identifiers and keywords (and, or, not, eq, ne, gt, ge, lt, le)
@@ -78,15 +95,24 @@ func (l *Lexer) NextToken() token.Token {
 		if offendingCh, isOk := l.peekChar(); isOk {
 			l.readChar()
 
-			return token.Token{Type: token.Illegal, Literal: string(offendingCh), Position: startPos}
+			return token.Token{Type: token.Illegal, Literal: string(offendingCh), Position: startPos, End: l.readPosition}
 		}
 
-		return token.Token{Type: token.EOF, Literal: "", Position: startPos}
+		return token.Token{Type: token.EOF, Literal: "", Position: startPos, End: startPos}
+	}
+
+	keyword := ident
+	if l.caseInsensitiveKeywords {
+		keyword = strings.ToLower(ident)
 	}
 
-	switch ident {
+	switch keyword {
 	case string(token.Null):
 		return newTokenFromType(token.Null, startPos)
+	case string(token.True):
+		return newTokenFromType(token.True, startPos)
+	case string(token.False):
+		return newTokenFromType(token.False, startPos)
 	case string(token.And):
 		return newTokenFromType(token.And, startPos)
 	case string(token.Or):
@@ -106,7 +132,7 @@ func (l *Lexer) NextToken() token.Token {
 	case string(token.LessThanOrEqual):
 		return newTokenFromType(token.LessThanOrEqual, startPos)
 	default:
-		return token.Token{Type: token.Ident, Literal: ident, Position: startPos}
+		return token.Token{Type: token.Ident, Literal: ident, Position: startPos, End: l.readPosition}
 	}
 }
 
@@ -150,25 +176,38 @@ func (l *Lexer) readWhile(pred func(byte) bool) string {
 }
 
 // readSingleQuoted reads content inside single quotes, consuming both quotes.
+// Two consecutive single quotes inside the literal are read as one escaped single quote.
 // If no closing quote is found, it reads until end and returns what was found (without the opening quote).
 func (l *Lexer) readSingleQuoted() string {
 	// consume opening quote
 	l.readChar()
 
+	var sb strings.Builder
+
 	start := l.readPosition
 	for ch, ok := l.peekChar(); ok; ch, ok = l.peekChar() {
 		if ch == '\'' {
-			// end of string
-			literal := l.input[start:l.readPosition]
-			l.readChar() // consume closing quote
+			sb.WriteString(l.input[start:l.readPosition])
+			l.readChar() // consume quote
 
-			return literal
+			if next, isOk := l.peekChar(); !isOk || next != '\'' {
+				// end of string
+				return sb.String()
+			}
+
+			// escaped quote, keep the second one as part of the literal
+			start = l.readPosition
+			l.readChar()
+
+			continue
 		}
 
 		l.readChar()
 	}
 	// EOF reached without closing quote
-	return l.input[start:l.readPosition]
+	sb.WriteString(l.input[start:l.readPosition])
+
+	return sb.String()
 }
 
 func isDigit(ch byte) bool { return ch >= '0' && ch <= '9' }
@@ -182,5 +221,5 @@ func isIdentChar(ch byte) bool {
 }
 
 func newTokenFromType(t token.Type, position int) token.Token {
-	return token.Token{Type: t, Literal: string(t), Position: position}
+	return token.Token{Type: t, Literal: string(t), Position: position, End: position + len(t)}
 }
@@ -28,6 +28,22 @@ func TestNextToken(t *testing.T) {
 				{token.EOF, ""},
 			},
 		},
+		"booleans": {
+			input: `active eq true and deleted eq false`,
+			expected: []struct {
+				expectedType    token.Type
+				expectedLiteral string
+			}{
+				{token.Ident, "active"},
+				{token.Eq, string(token.Eq)},
+				{token.True, "true"},
+				{token.And, string(token.And)},
+				{token.Ident, "deleted"},
+				{token.Eq, string(token.Eq)},
+				{token.False, "false"},
+				{token.EOF, ""},
+			},
+		},
 		"simple equal null": {
 			input: `key eq null`,
 			expected: []struct {
@@ -40,6 +56,22 @@ func TestNextToken(t *testing.T) {
 				{token.EOF, ""},
 			},
 		},
+		"escaped single quote in string": {
+			input: `name eq 'O''Neil' or name eq ''''`,
+			expected: []struct {
+				expectedType    token.Type
+				expectedLiteral string
+			}{
+				{token.Ident, "name"},
+				{token.Eq, string(token.Eq)},
+				{token.String, "O'Neil"},
+				{token.Or, string(token.Or)},
+				{token.Ident, "name"},
+				{token.Eq, string(token.Eq)},
+				{token.String, "'"},
+				{token.EOF, ""},
+			},
+		},
 		"simple not equal null": {
 			input: `key ne null`,
 			expected: []struct {
@@ -265,3 +297,61 @@ func TestNextToken(t *testing.T) {
 		})
 	}
 }
+
+func TestNextTokenSpan(t *testing.T) {
+	t.Parallel()
+
+	l := New("name eq 'John'")
+
+	expected := []struct {
+		position int
+		end      int
+	}{
+		{0, 4},
+		{5, 7},
+		{8, 14},
+		{14, 14},
+	}
+
+	for i, tt := range expected {
+		tok := l.NextToken()
+
+		if tok.Position != tt.position || tok.End != tt.end {
+			t.Fatalf("tests[%d] - span wrong, expected=[%d,%d), got=[%d,%d)", i, tt.position, tt.end, tok.Position, tok.End)
+		}
+	}
+}
+
+func TestNextTokenCaseInsensitiveKeywords(t *testing.T) {
+	t.Parallel()
+
+	l := New("Name EQ 'John' AND not Age Gt 18 Or email ne NULL", WithCaseInsensitiveKeywords())
+
+	expected := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.Ident, "Name"},
+		{token.Eq, "eq"},
+		{token.String, "John"},
+		{token.And, "and"},
+		{token.Not, "not"},
+		{token.Ident, "Age"},
+		{token.GreaterThan, "gt"},
+		{token.Int, "18"},
+		{token.Or, "or"},
+		{token.Ident, "email"},
+		{token.NotEq, "ne"},
+		{token.Null, "null"},
+		{token.EOF, ""},
+	}
+
+	for i, tt := range expected {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - wrong token, expected=%q %q, got=%q %q",
+				i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+}
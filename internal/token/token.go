@@ -13,6 +13,8 @@ const (
 	Int    Type = "Int"
 	String Type = "String"
 	Null   Type = "null"
+	True   Type = "true"
+	False  Type = "false"
 
 	/* Comparison Operators. */
 
@@ -46,5 +48,7 @@ type (
 		Literal string
 		// Position of the token.
 		Position int
+		// End is the position right after the last character of the token.
+		End int
 	}
 )
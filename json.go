@@ -0,0 +1,306 @@
+package goqrius
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrInvalidJSON is returned when a JSON document does not describe a valid Expression, see UnmarshalExpression.
+var ErrInvalidJSON = errors.New("invalid JSON expression")
+
+// The values of the "type" field of every node in the JSON format.
+const (
+	jsonAnd        = "and"
+	jsonOr         = "or"
+	jsonNot        = "not"
+	jsonFilter     = "filter"
+	jsonIdentifier = "identifier"
+	jsonInteger    = "integer"
+	jsonString     = "string"
+	jsonBoolean    = "boolean"
+	jsonNull       = "null"
+	jsonBad        = "bad"
+	jsonMissing    = "missing"
+)
+
+//go:embed schema.json
+var jsonSchema []byte
+
+// JSONSchema returns the JSON Schema, draft 2020-12, of the JSON format of the expressions, e.g.:
+//
+//	{"type":"filter","left":{"type":"identifier","value":"name"},"operator":"eq","right":{"type":"string","value":"John"}}
+func JSONSchema() []byte {
+	return slices.Clone(jsonSchema)
+}
+
+type (
+	// jsonNode is the JSON representation of every node, discriminated by Type.
+	jsonNode struct {
+		Type     string         `json:"type"`
+		Left     Expression     `json:"left,omitempty"`
+		Operator FilterOperator `json:"operator,omitempty"`
+		Right    Expression     `json:"right,omitempty"`
+		Value    any            `json:"value,omitempty"`
+		Span     Span           `json:"span,omitzero"`
+	}
+
+	// rawJSONNode is the jsonNode before decoding its children.
+	rawJSONNode struct {
+		Type     string          `json:"type"`
+		Left     json.RawMessage `json:"left"`
+		Operator FilterOperator  `json:"operator"`
+		Right    json.RawMessage `json:"right"`
+		Value    json.RawMessage `json:"value"`
+		Span     Span            `json:"span"`
+	}
+)
+
+func (ae *AndExpr) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonNode{Type: jsonAnd, Left: ae.Left, Right: ae.Right, Span: ae.Span})
+}
+
+func (ae *AndExpr) UnmarshalJSON(data []byte) error { return unmarshalNode(data, ae) }
+
+func (oe *OrExpr) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonNode{Type: jsonOr, Left: oe.Left, Right: oe.Right, Span: oe.Span})
+}
+
+func (oe *OrExpr) UnmarshalJSON(data []byte) error { return unmarshalNode(data, oe) }
+
+func (ne *NotExpr) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonNode{Type: jsonNot, Right: ne.Right, Span: ne.Span})
+}
+
+func (ne *NotExpr) UnmarshalJSON(data []byte) error { return unmarshalNode(data, ne) }
+
+func (ie *FilterExpr) MarshalJSON() ([]byte, error) {
+	node := jsonNode{Type: jsonFilter, Operator: ie.Operator, Span: ie.Span}
+	// avoid a typed nil in the Left interface.
+	if ie.Left != nil {
+		node.Left = ie.Left
+	}
+
+	if ie.Right != nil {
+		node.Right = ie.Right
+	}
+
+	return json.Marshal(node)
+}
+
+func (ie *FilterExpr) UnmarshalJSON(data []byte) error { return unmarshalNode(data, ie) }
+
+func (i *Identifier) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonNode{Type: jsonIdentifier, Value: i.Value, Span: i.Span})
+}
+
+func (i *Identifier) UnmarshalJSON(data []byte) error { return unmarshalNode(data, i) }
+
+func (il *IntegerLiteral) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonNode{Type: jsonInteger, Value: il.Value, Span: il.Span})
+}
+
+func (il *IntegerLiteral) UnmarshalJSON(data []byte) error { return unmarshalNode(data, il) }
+
+func (bl *BooleanLiteral) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonNode{Type: jsonBoolean, Value: bl.Value, Span: bl.Span})
+}
+
+func (bl *BooleanLiteral) UnmarshalJSON(data []byte) error { return unmarshalNode(data, bl) }
+
+func (n *Null) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonNode{Type: jsonNull, Span: n.Span})
+}
+
+func (n *Null) UnmarshalJSON(data []byte) error { return unmarshalNode(data, n) }
+
+func (sl *StringLiteral) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonNode{Type: jsonString, Value: sl.Value, Span: sl.Span})
+}
+
+func (sl *StringLiteral) UnmarshalJSON(data []byte) error { return unmarshalNode(data, sl) }
+
+func (be *BadExpr) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonNode{Type: jsonBad, Span: be.Span})
+}
+
+func (be *BadExpr) UnmarshalJSON(data []byte) error { return unmarshalNode(data, be) }
+
+func (me *MissingExpr) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonNode{Type: jsonMissing, Span: me.Span})
+}
+
+func (me *MissingExpr) UnmarshalJSON(data []byte) error { return unmarshalNode(data, me) }
+
+// UnmarshalExpression decodes an Expression from its JSON representation, created with json.Marshal.
+// The concrete node is chosen by the "type" field, see JSONSchema.
+// The JSON null is decoded as a nil Expression.
+func UnmarshalExpression(data []byte) (Expression, error) {
+	var raw *rawJSONNode
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidJSON, err)
+	}
+
+	if raw == nil {
+		//nolint:nilnil // null is the JSON representation of a nil Expression.
+		return nil, nil
+	}
+
+	return raw.expression()
+}
+
+// child decodes the child node called name, that is required.
+func (r *rawJSONNode) child(data json.RawMessage, name string) (Expression, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, fmt.Errorf("%w: missing %s of %s", ErrInvalidJSON, name, r.Type)
+	}
+
+	return UnmarshalExpression(data)
+}
+
+// unmarshalNode decodes data into dst, failing if data describes another type of node.
+func unmarshalNode[T any, P interface {
+	*T
+	Expression
+}](data []byte, dst P) error {
+	expr, err := UnmarshalExpression(data)
+	if err != nil {
+		return err
+	}
+
+	src, ok := expr.(P)
+	if !ok {
+		return fmt.Errorf("%w: can not decode %T into %T", ErrInvalidJSON, expr, dst)
+	}
+
+	*dst = *src
+
+	return nil
+}
+
+func (r *rawJSONNode) expression() (Expression, error) {
+	switch r.Type {
+	case jsonAnd, jsonOr:
+		left, err := r.child(r.Left, "left")
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := r.child(r.Right, "right")
+		if err != nil {
+			return nil, err
+		}
+
+		if r.Type == jsonAnd {
+			return &AndExpr{Left: left, Right: right, Span: r.Span}, nil
+		}
+
+		return &OrExpr{Left: left, Right: right, Span: r.Span}, nil
+	case jsonNot:
+		right, err := r.child(r.Right, "right")
+		if err != nil {
+			return nil, err
+		}
+
+		return &NotExpr{Right: right, Span: r.Span}, nil
+	case jsonFilter:
+		return r.filter()
+	case jsonIdentifier, jsonInteger, jsonString:
+		return r.literal()
+	case jsonBoolean:
+		var value bool
+		if err := r.value(&value); err != nil {
+			return nil, err
+		}
+
+		return &BooleanLiteral{Value: value, Span: r.Span}, nil
+	case jsonNull:
+		return &Null{Span: r.Span}, nil
+	case jsonBad:
+		return &BadExpr{Span: r.Span}, nil
+	case jsonMissing:
+		return &MissingExpr{Span: r.Span}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidJSON, r.Type)
+	}
+}
+
+func (r *rawJSONNode) filter() (Expression, error) {
+	switch r.Operator {
+	case Eq, NotEq, GreaterThan, GreaterThanOrEqual, LessThan, LessThanOrEqual:
+	default:
+		return nil, fmt.Errorf("%w: unknown operator %q", ErrInvalidJSON, r.Operator)
+	}
+
+	left, err := r.child(r.Left, "left")
+	if err != nil {
+		return nil, err
+	}
+
+	ident, ok := left.(*Identifier)
+	if !ok {
+		return nil, fmt.Errorf("%w: left side of a filter must be an identifier, got %T", ErrInvalidJSON, left)
+	}
+
+	right, err := r.child(r.Right, "right")
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := right.(Value)
+	if !ok {
+		return nil, fmt.Errorf("%w: right side of a filter must be a value, got %T", ErrInvalidJSON, right)
+	}
+
+	return &FilterExpr{Left: ident, Operator: r.Operator, Right: value, Span: r.Span}, nil
+}
+
+func (r *rawJSONNode) literal() (Expression, error) {
+	var value string
+	if err := r.value(&value); err != nil {
+		return nil, err
+	}
+
+	switch r.Type {
+	case jsonIdentifier:
+		return &Identifier{Value: value, Span: r.Span}, nil
+	case jsonInteger:
+		if !isDigits(value) {
+			return nil, fmt.Errorf("%w: %q is not an integer", ErrInvalidJSON, value)
+		}
+
+		return &IntegerLiteral{Value: value, Span: r.Span}, nil
+	default:
+		return &StringLiteral{Value: value, Span: r.Span}, nil
+	}
+}
+
+// isDigits reports whether s is a non empty sequence of decimal digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, c := range []byte(s) {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// value decodes the value field into v, that must be present.
+func (r *rawJSONNode) value(v any) error {
+	if len(r.Value) == 0 || string(r.Value) == "null" {
+		return fmt.Errorf("%w: missing value of %s", ErrInvalidJSON, r.Type)
+	}
+
+	if err := json.Unmarshal(r.Value, v); err != nil {
+		return fmt.Errorf("%w: invalid value of %s: %w", ErrInvalidJSON, r.Type, err)
+	}
+
+	return nil
+}
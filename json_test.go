@@ -0,0 +1,190 @@
+package goqrius
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		expr     Expression
+		expected string
+	}{
+		"filter": {
+			expr: &FilterExpr{Left: &Identifier{Value: "name"}, Operator: Eq, Right: &StringLiteral{Value: "John"}},
+			expected: `{"type":"filter","left":{"type":"identifier","value":"name"},"operator":"eq",` +
+				`"right":{"type":"string","value":"John"}}`,
+		},
+		"empty string is kept": {
+			expr:     &StringLiteral{Value: ""},
+			expected: `{"type":"string","value":""}`,
+		},
+		"and with span": {
+			expr: MustParse("a eq 1 and b ne null"),
+			expected: `{"type":"and",` +
+				`"left":{"type":"filter","left":{"type":"identifier","value":"a","span":{"start":0,"end":1}},` +
+				`"operator":"eq","right":{"type":"integer","value":"1","span":{"start":5,"end":6}},"span":{"start":0,"end":6}},` +
+				`"right":{"type":"filter","left":{"type":"identifier","value":"b","span":{"start":11,"end":12}},` +
+				`"operator":"ne","right":{"type":"null","span":{"start":16,"end":20}},"span":{"start":11,"end":20}},` +
+				`"span":{"start":0,"end":20}}`,
+		},
+		"boolean": {
+			expr:     &FilterExpr{Left: &Identifier{Value: "active"}, Operator: Eq, Right: &BooleanLiteral{Value: false}},
+			expected: `{"type":"filter","left":{"type":"identifier","value":"active"},"operator":"eq","right":{"type":"boolean","value":false}}`,
+		},
+		"not": {
+			expr:     &NotExpr{Right: &FilterExpr{Left: &Identifier{Value: "a"}, Operator: LessThan, Right: &IntegerLiteral{Value: "1"}}},
+			expected: `{"type":"not","right":{"type":"filter","left":{"type":"identifier","value":"a"},"operator":"lt","right":{"type":"integer","value":"1"}}}`,
+		},
+		"placeholders": {
+			expr:     &OrExpr{Left: &BadExpr{}, Right: &MissingExpr{}},
+			expected: `{"type":"or","left":{"type":"bad"},"right":{"type":"missing"}}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := json.Marshal(tt.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(got) != tt.expected {
+				t.Fatalf("unexpected json.\nexpected=%s\ngot=     %s", tt.expected, got)
+			}
+
+			decoded, err := UnmarshalExpression(got)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !Equal(decoded, tt.expr) {
+				t.Fatalf("decoded expression is not equal. expected=%s got=%s", tt.expr, decoded)
+			}
+		})
+	}
+}
+
+func TestUnmarshalExpressionKeepsSpans(t *testing.T) {
+	t.Parallel()
+
+	expr := MustParse("name eq 'O''Neil' or not (age gt 18)")
+
+	data, err := json.Marshal(expr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	decoded, err := UnmarshalExpression(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var spans, decodedSpans []Span
+
+	Inspect(expr, func(e Expression) bool {
+		if e != nil {
+			spans = append(spans, Span{Start: e.Pos(), End: e.End()})
+		}
+
+		return true
+	})
+	Inspect(decoded, func(e Expression) bool {
+		if e != nil {
+			decodedSpans = append(decodedSpans, Span{Start: e.Pos(), End: e.End()})
+		}
+
+		return true
+	})
+
+	if len(spans) != len(decodedSpans) {
+		t.Fatalf("unexpected number of nodes. expected=%d got=%d", len(spans), len(decodedSpans))
+	}
+
+	for i := range spans {
+		if spans[i] != decodedSpans[i] {
+			t.Fatalf("node %d - unexpected span. expected=%v got=%v", i, spans[i], decodedSpans[i])
+		}
+	}
+}
+
+func TestUnmarshalJSONConcreteNode(t *testing.T) {
+	t.Parallel()
+
+	var filter FilterExpr
+	if err := json.Unmarshal([]byte(`{"type":"filter","left":{"type":"identifier","value":"age"},"operator":"ge","right":{"type":"integer","value":"18"}}`), &filter); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !Equal(&filter, MustParse("age ge 18")) {
+		t.Fatalf("unexpected filter %s", &filter)
+	}
+
+	var and AndExpr
+	if err := json.Unmarshal([]byte(`{"type":"or"}`), &and); !errors.Is(err, ErrInvalidJSON) {
+		t.Fatalf("expected ErrInvalidJSON, got %v", err)
+	}
+}
+
+func TestUnmarshalExpressionErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"not json":               `{`,
+		"unknown type":           `{"type":"xor"}`,
+		"missing type":           `{}`,
+		"unknown operator":       `{"type":"filter","operator":"like"}`,
+		"left is not identifier": `{"type":"filter","operator":"eq","left":{"type":"integer","value":"1"}}`,
+		"right is not value":     `{"type":"filter","operator":"eq","right":{"type":"identifier","value":"a"}}`,
+		"missing value":          `{"type":"string"}`,
+		"invalid child":          `{"type":"and","left":{"type":"xor"}}`,
+		"and without children":   `{"type":"and"}`,
+		"or without right":       `{"type":"or","left":{"type":"bad"}}`,
+		"not without right":      `{"type":"not"}`,
+		"null child":             `{"type":"not","right":null}`,
+		"filter without left":    `{"type":"filter","operator":"eq","right":{"type":"null"}}`,
+		"filter without right":   `{"type":"filter","operator":"eq","left":{"type":"identifier","value":"a"}}`,
+		"integer with letters":   `{"type":"integer","value":"1e3"}`,
+		"empty integer":          `{"type":"integer","value":""}`,
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := UnmarshalExpression([]byte(input)); !errors.Is(err, ErrInvalidJSON) {
+				t.Fatalf("expected ErrInvalidJSON, got %v", err)
+			}
+		})
+	}
+}
+
+func TestUnmarshalExpressionNull(t *testing.T) {
+	t.Parallel()
+
+	expr, err := UnmarshalExpression([]byte("null"))
+	if err != nil || expr != nil {
+		t.Fatalf("expected a nil expression, got %v, %v", expr, err)
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	var schema map[string]any
+	if err := json.Unmarshal(JSONSchema(), &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+
+	defs, _ := schema["$defs"].(map[string]any)
+	for _, typ := range []string{jsonAnd, jsonOr, jsonNot, jsonFilter, jsonIdentifier, jsonInteger, jsonString, jsonNull, jsonBad, jsonMissing} {
+		if _, ok := defs[typ]; !ok {
+			t.Fatalf("schema does not define %q", typ)
+		}
+	}
+}
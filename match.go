@@ -0,0 +1,242 @@
+package goqrius
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// TagName is the struct tag used to name the fields in the filter expressions, e.g. `goqrius:"name"`,
+// followed by the options described in SchemaFromStruct.
+const TagName = "goqrius"
+
+// Match reports whether v matches expr, following the same semantics as Eval.
+//
+// v can be a struct, a map with string keys, or a pointer to them. The identifiers are resolved:
+//   - in structs, by the name in the goqrius tag, then by the name in the json tag, and then by the Go field name,
+//     and by the aliases of the goqrius tag.
+//     Fields tagged with "-" and unexported fields are ignored,
+//     and the fields of the embedded structs are promoted, following the rules of encoding/json:
+//     the shallower field wins, and between fields at the same depth the only tagged one,
+//     otherwise the name is ambiguous and it's not resolved. The name of a field wins over an alias of another one.
+//   - in maps, by the key.
+//
+// A dotted identifier, e.g. `address.city`, reads the nested structs and maps, following the pointers.
+func Match(expr Expression, v any, opts ...EvalOption) (bool, error) {
+	e := evaluator{options: newEvalOptions(opts...), resolve: reflectResolver(reflect.ValueOf(v))}
+
+	t, err := e.eval(expr)
+
+	return t == truthTrue, err
+}
+
+func reflectResolver(root reflect.Value) resolver {
+	return func(path string) (any, bool) {
+		current := root
+
+		for segment := range strings.SplitSeq(path, ".") {
+			current = indirect(current)
+			if !current.IsValid() {
+				// the navigation through a null is null.
+				return nil, true
+			}
+
+			var ok bool
+
+			current, ok = lookup(current, segment)
+			if !ok {
+				return nil, false
+			}
+		}
+
+		if !current.IsValid() {
+			return nil, true
+		}
+
+		if !current.CanInterface() {
+			return nil, false
+		}
+
+		return current.Interface(), true
+	}
+}
+
+// indirect follows the pointers and interfaces of v, returning an invalid value for nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+
+		v = v.Elem()
+	}
+
+	return v
+}
+
+// lookup returns the field or map entry called name in v.
+// The returned value is invalid if it is reached through a nil embedded pointer.
+func lookup(v reflect.Value, name string) (reflect.Value, bool) {
+	switch v.Kind() {
+	case reflect.Struct:
+		index, ok := structFields(v.Type())[name]
+		if !ok {
+			return reflect.Value{}, false
+		}
+
+		field, err := v.FieldByIndexErr(index)
+		if err != nil {
+			return reflect.Value{}, true
+		}
+
+		return field, true
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}, false
+		}
+
+		value := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+
+		return value, value.IsValid()
+	default:
+		return reflect.Value{}, false
+	}
+}
+
+//nolint:gochecknoglobals // cache of the fields of the struct types, as encoding/json does.
+var fieldsCache sync.Map
+
+// structFields returns the index of every field of t by its name in the filter expressions.
+func structFields(t reflect.Type) map[string][]int {
+	if fields, ok := fieldsCache.Load(t); ok {
+		return fields.(map[string][]int) //nolint:forcetypeassert // only maps are stored.
+	}
+
+	candidates := make(map[string][]fieldCandidate)
+	collectFields(t, nil, candidates, map[reflect.Type]bool{t: true})
+
+	fields := make(map[string][]int, len(candidates))
+	for name, named := range candidates {
+		if index, ok := dominantField(named); ok {
+			fields[name] = index
+		}
+	}
+
+	actual, _ := fieldsCache.LoadOrStore(t, fields)
+
+	return actual.(map[string][]int) //nolint:forcetypeassert // only maps are stored.
+}
+
+// fieldCandidate is a field that can be resolved by a name, with its index, whether the name comes from a tag,
+// and whether it's one of its aliases.
+type fieldCandidate struct {
+	index  []int
+	tagged bool
+	alias  bool
+}
+
+// collectFields adds the fields of t to candidates, by name, promoting the fields of the embedded structs not in
+// embedding, to avoid cycles of embedded pointers.
+func collectFields(t reflect.Type, index []int, candidates map[string][]fieldCandidate, embedding map[reflect.Type]bool) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+
+		name, tagged := fieldName(f)
+		if name == "-" {
+			continue
+		}
+
+		fieldIndex := append(append([]int{}, index...), i)
+
+		if f.Anonymous && !tagged {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				if !embedding[ft] {
+					embedding[ft] = true
+					collectFields(ft, fieldIndex, candidates, embedding)
+					delete(embedding, ft)
+				}
+
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		candidates[name] = append(candidates[name], fieldCandidate{index: fieldIndex, tagged: tagged})
+
+		// the invalid options are reported by SchemaFromStruct.
+		opts, _ := parseTagOptions(f.Tag.Get(TagName))
+		for _, alias := range opts.aliases {
+			if alias != name {
+				candidates[alias] = append(candidates[alias], fieldCandidate{index: fieldIndex, tagged: true, alias: true})
+			}
+		}
+	}
+}
+
+// dominantField returns the index of the field a name resolves to, following the rules of encoding/json:
+// the shallowest field wins, and between fields at the same depth the only tagged one,
+// otherwise the name is ambiguous and it's dropped.
+// The name of a field wins over the aliases of the others at the same depth.
+func dominantField(candidates []fieldCandidate) ([]int, bool) {
+	depth := len(candidates[0].index)
+	for _, c := range candidates[1:] {
+		depth = min(depth, len(c.index))
+	}
+
+	onlyAliases := true
+
+	for _, c := range candidates {
+		if len(c.index) == depth && !c.alias {
+			onlyAliases = false
+		}
+	}
+
+	var (
+		dominant    []fieldCandidate
+		taggedCount int
+	)
+
+	for _, c := range candidates {
+		if len(c.index) == depth && (onlyAliases || !c.alias) {
+			dominant = append(dominant, c)
+
+			if c.tagged {
+				taggedCount++
+			}
+		}
+	}
+
+	switch {
+	case len(dominant) == 1:
+		return dominant[0].index, true
+	case taggedCount == 1:
+		for _, c := range dominant {
+			if c.tagged {
+				return c.index, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// fieldName returns the name of the field in the filter expressions, and whether it comes from a tag.
+func fieldName(f reflect.StructField) (string, bool) {
+	for _, tag := range []string{TagName, "json"} {
+		if value, ok := f.Tag.Lookup(tag); ok {
+			if name, _, _ := strings.Cut(value, ","); name != "" {
+				return name, true
+			}
+		}
+	}
+
+	return f.Name, false
+}
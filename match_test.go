@@ -0,0 +1,173 @@
+package goqrius
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type (
+	matchAddress struct {
+		City string `json:"city"`
+		Zip  *int   `json:"zip,omitempty"`
+	}
+
+	matchAudit struct {
+		Created time.Time `json:"created"`
+		Deleted *time.Time
+	}
+
+	matchBase struct {
+		ID   int64  `json:"id"`
+		Name string `json:"base_name"`
+	}
+
+	matchUser struct {
+		matchBase
+		*matchAudit
+
+		Name     string            `goqrius:"name" json:"fullName"`
+		Age      int               `json:"age"`
+		Email    *string           `json:"email"`
+		Active   bool              `json:"active"`
+		Password string            `goqrius:"-"`
+		Address  *matchAddress     `json:"address"`
+		Labels   map[string]string `json:"labels"`
+		secret   string
+	}
+)
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	email := "john.doe@example.com"
+	user := &matchUser{
+		matchBase:  matchBase{ID: 1, Name: "base"},
+		matchAudit: &matchAudit{Created: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+		Name:       "John",
+		Age:        20,
+		Email:      &email,
+		Active:     true,
+		Password:   "secret",
+		Address:    &matchAddress{City: "Madrid"},
+		Labels:     map[string]string{"team": "core"},
+		secret:     "secret",
+	}
+
+	tests := map[string]struct {
+		filter   string
+		expected bool
+	}{
+		"goqrius tag wins over json": {filter: "name eq 'John'", expected: true},
+		"json tag":                   {filter: "age gt 18 and active eq true", expected: true},
+		"pointer field":              {filter: "email ne null and email eq 'john.doe@example.com'", expected: true},
+		"promoted field":             {filter: "id eq 1", expected: true},
+		"shallower field wins":       {filter: "base_name eq 'base'", expected: true},
+		"promoted from embedded ptr": {filter: "created lt '2025-02-01T00:00:00Z'", expected: true},
+		"field name without tags":    {filter: "Deleted eq null", expected: true},
+		"nested struct":              {filter: "address.city eq 'Madrid'", expected: true},
+		"nil nested pointer":         {filter: "address.zip eq null", expected: true},
+		"map":                        {filter: "labels.team eq 'core'", expected: true},
+		"not":                        {filter: "not (age lt 18 or name eq 'Jane')", expected: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Match(MustParse(tt.filter), user)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.expected {
+				t.Fatalf("unexpected result for %q. expected=%t got=%t", tt.filter, tt.expected, got)
+			}
+
+			// the value is also matched when it's not a pointer.
+			if got, err = Match(MustParse(tt.filter), *user); err != nil || got != tt.expected {
+				t.Fatalf("unexpected result for the struct value: %t, %v", got, err)
+			}
+		})
+	}
+}
+
+func TestMatchNotFound(t *testing.T) {
+	t.Parallel()
+
+	user := matchUser{Name: "John"}
+
+	tests := map[string]string{
+		"excluded field":      "Password eq 'secret'",
+		"unexported field":    "secret eq 'secret'",
+		"renamed field":       "fullName eq 'John'",
+		"go name when tagged": "Age eq 20",
+		"missing map key":     "labels.team eq 'core'",
+		"field of a string":   "name.first eq 'J'",
+	}
+
+	for name, filter := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Match(MustParse(filter), user); !errors.Is(err, ErrFieldNotFound) {
+				t.Fatalf("expected ErrFieldNotFound, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMatchNilEmbeddedPointer(t *testing.T) {
+	t.Parallel()
+
+	got, err := Match(MustParse("created eq null"), matchUser{})
+	if err != nil || !got {
+		t.Fatalf("expected a field of a nil embedded pointer to be null, got %t, %v", got, err)
+	}
+}
+
+func TestMatchEmbeddedConflicts(t *testing.T) {
+	t.Parallel()
+
+	type (
+		first struct {
+			Code  string `goqrius:"code"`
+			Label string
+		}
+		second struct {
+			Code  string `goqrius:"code"`
+			Label string `json:"Label"`
+		}
+		conflicts struct {
+			first
+			second
+		}
+	)
+
+	v := conflicts{first: first{Code: "a", Label: "first"}, second: second{Code: "b", Label: "second"}}
+
+	if _, err := Match(MustParse("code eq 'a'"), v); !errors.Is(err, ErrFieldNotFound) {
+		t.Fatalf("expected an ambiguous field to not be found, got %v", err)
+	}
+
+	got, err := Match(MustParse("Label eq 'second'"), v)
+	if err != nil || !got {
+		t.Fatalf("expected the tagged field to win, got %t, %v", got, err)
+	}
+
+	predicate, err := Compile[conflicts](MustParse("Label eq 'second'"))
+	if err != nil || !predicate(v) {
+		t.Fatalf("expected the tagged field to compile and win, got %v", err)
+	}
+}
+
+func TestMatchMap(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{"user": matchUser{Name: "John"}}
+
+	got, err := Match(MustParse("user.name eq 'John'"), data)
+	if err != nil || !got {
+		t.Fatalf("expected a match, got %t, %v", got, err)
+	}
+}
@@ -2,6 +2,7 @@ package goqrius
 
 import (
 	"fmt"
+	"strconv"
 )
 
 type FilterOperator string
@@ -22,11 +23,25 @@ var (
 	_ Value           = new(IntegerLiteral)
 	_ Value           = new(Null)
 	_ Value           = new(StringLiteral)
+	_ Value           = new(BooleanLiteral)
+	_ Value           = new(BadExpr)
+	_ Value           = new(MissingExpr)
 )
 
 type (
 	Node interface {
 		String() string
+		// Pos returns the position of the first character of the node in the filter expression.
+		Pos() int
+		// End returns the position right after the last character of the node in the filter expression.
+		End() int
+	}
+
+	// Span is the range of positions covered by a node in the filter expression, [Start, End).
+	// Nodes not created by the parser have a zero Span.
+	Span struct {
+		Start int `json:"start"`
+		End   int `json:"end"`
 	}
 
 	Expression interface {
@@ -42,6 +57,8 @@ type (
 	// Value is a marker interface to indicate that the node is a value, e.g., null, 'John', 5, etc.
 	Value interface {
 		Expression
+		// GoValue returns the value as a Go value: int64, string, bool or nil for null.
+		GoValue() (any, error)
 		valueNode()
 	}
 
@@ -49,17 +66,20 @@ type (
 	AndExpr struct {
 		Left  Expression
 		Right Expression
+		Span  Span
 	}
 
 	// OrExpr or concatenates FilterExpr.
 	OrExpr struct {
 		Left  Expression
 		Right Expression
+		Span  Span
 	}
 
 	// NotExpr negates an Expression.
 	NotExpr struct {
 		Right Expression
+		Span  Span
 	}
 
 	// FilterExpr represents a key and operator and a value in a filter clause.
@@ -67,59 +87,118 @@ type (
 		Left     *Identifier
 		Operator FilterOperator
 		Right    Value
+		Span     Span
 	}
 
 	// Identifier is the Expression to indicate the key of a filter clause, e.g. `name`.
 	Identifier struct {
 		Value string
+		Span  Span
 	}
 
 	// IntegerLiteral is the Expression to indicate an int value of a filter clause, e.g. `1`.
 	IntegerLiteral struct {
 		Value string
+		Span  Span
 	}
 
 	// Null is the Expression to indicate a value that is null.
-	Null struct{}
+	Null struct {
+		Span Span
+	}
 
 	// StringLiteral is the Expression to indicate an int value of a filter clause, e.g. `'John'`.
 	StringLiteral struct {
 		Value string
+		Span  Span
+	}
+
+	// BooleanLiteral is the Expression to indicate a boolean value of a filter clause, e.g. `true`.
+	// It's the node of the Bool accessor, as bool fields can't be compared with the other literals.
+	BooleanLiteral struct {
+		Value bool
+		Span  Span
+	}
+
+	// BadExpr is a placeholder for a part of the filter expression that could not be parsed.
+	// It's only found in the partial Expression returned together with a parse error.
+	BadExpr struct {
+		Span Span
+	}
+
+	// MissingExpr is a placeholder for an expression or value that is missing, e.g. after `and` in `name eq 1 and`.
+	// It's only found in the partial Expression returned together with a parse error.
+	MissingExpr struct {
+		Span Span
 	}
 )
 
 func (ae *AndExpr) String() string {
 	return fmt.Sprintf("(%s and %s)", ae.Left.String(), ae.Right.String())
 }
+func (ae *AndExpr) Pos() int                   { return ae.Span.Start }
+func (ae *AndExpr) End() int                   { return ae.Span.End }
 func (ae *AndExpr) expressionNode()            {}
 func (ae *AndExpr) logicalOperatorExpression() {}
 
 func (oe *OrExpr) String() string {
 	return fmt.Sprintf("(%s or %s)", oe.Left.String(), oe.Right.String())
 }
+func (oe *OrExpr) Pos() int                   { return oe.Span.Start }
+func (oe *OrExpr) End() int                   { return oe.Span.End }
 func (oe *OrExpr) expressionNode()            {}
 func (oe *OrExpr) logicalOperatorExpression() {}
 
 func (ne *NotExpr) String() string             { return fmt.Sprintf("(not %s)", ne.Right.String()) }
+func (ne *NotExpr) Pos() int                   { return ne.Span.Start }
+func (ne *NotExpr) End() int                   { return ne.Span.End }
 func (ne *NotExpr) expressionNode()            {}
 func (ne *NotExpr) logicalOperatorExpression() {}
 
 func (ie *FilterExpr) String() string {
 	return fmt.Sprintf("(%s %s %s)", ie.Left.String(), string(ie.Operator), ie.Right.String())
 }
+func (ie *FilterExpr) Pos() int        { return ie.Span.Start }
+func (ie *FilterExpr) End() int        { return ie.Span.End }
 func (ie *FilterExpr) expressionNode() {}
 
 func (i *Identifier) String() string  { return i.Value }
+func (i *Identifier) Pos() int        { return i.Span.Start }
+func (i *Identifier) End() int        { return i.Span.End }
 func (i *Identifier) expressionNode() {}
 
 func (il *IntegerLiteral) String() string  { return il.Value }
+func (il *IntegerLiteral) Pos() int        { return il.Span.Start }
+func (il *IntegerLiteral) End() int        { return il.Span.End }
 func (il *IntegerLiteral) expressionNode() {}
 func (il *IntegerLiteral) valueNode()      {}
 
 func (n *Null) String() string  { return "null" }
+func (n *Null) Pos() int        { return n.Span.Start }
+func (n *Null) End() int        { return n.Span.End }
 func (n *Null) expressionNode() {}
 func (n *Null) valueNode()      {}
 
 func (sl *StringLiteral) String() string  { return fmt.Sprintf("'%s'", sl.Value) }
+func (sl *StringLiteral) Pos() int        { return sl.Span.Start }
+func (sl *StringLiteral) End() int        { return sl.Span.End }
 func (sl *StringLiteral) expressionNode() {}
 func (sl *StringLiteral) valueNode()      {}
+
+func (bl *BooleanLiteral) String() string  { return strconv.FormatBool(bl.Value) }
+func (bl *BooleanLiteral) Pos() int        { return bl.Span.Start }
+func (bl *BooleanLiteral) End() int        { return bl.Span.End }
+func (bl *BooleanLiteral) expressionNode() {}
+func (bl *BooleanLiteral) valueNode()      {}
+
+func (be *BadExpr) String() string  { return "<bad>" }
+func (be *BadExpr) Pos() int        { return be.Span.Start }
+func (be *BadExpr) End() int        { return be.Span.End }
+func (be *BadExpr) expressionNode() {}
+func (be *BadExpr) valueNode()      {}
+
+func (me *MissingExpr) String() string  { return "<missing>" }
+func (me *MissingExpr) Pos() int        { return me.Span.Start }
+func (me *MissingExpr) End() int        { return me.Span.End }
+func (me *MissingExpr) expressionNode() {}
+func (me *MissingExpr) valueNode()      {}
@@ -0,0 +1,133 @@
+package goqrius
+
+import (
+	"github.com/golaxo/goqrius/internal/lexer"
+)
+
+const (
+	// DefaultMaxErrors is the maximum number of errors reported by default, see WithMaxErrors.
+	DefaultMaxErrors = 10
+	// DefaultMaxDepth is the maximum nesting depth of an expression by default, see WithMaxDepth.
+	DefaultMaxDepth = 256
+)
+
+type (
+	// ParseOption configures how a filter expression is parsed.
+	ParseOption func(*parseOptions)
+
+	parseOptions struct {
+		fields                  []string
+		maxErrors               int
+		caseInsensitiveKeywords bool
+		int64Range              bool
+		schema                  Schema
+		maxLength               int
+		maxDepth                int
+		maxNodes                int
+		maxOrBranches           int
+		maxFields               int
+	}
+)
+
+// WithFields declares the fields that can be used in the filter expression.
+// Any other identifier is reported as an unknown field, suggesting the closest declared ones.
+func WithFields(fields ...string) ParseOption {
+	return func(o *parseOptions) {
+		o.fields = append(o.fields, fields...)
+	}
+}
+
+// WithMaxErrors sets the maximum number of errors reported, DefaultMaxErrors by default.
+// Once reached, the parsing stops and a final error with CodeTooManyErrors is added.
+// A value lower or equal than 0 reports all the errors.
+func WithMaxErrors(maxErrors int) ParseOption {
+	return func(o *parseOptions) {
+		o.maxErrors = maxErrors
+	}
+}
+
+// WithCaseInsensitiveKeywords accepts the keywords in any casing, e.g. `Name EQ 'John' AND age GT 18`.
+// Notice that identifiers that match a keyword, e.g. `NULL`, are then read as that keyword.
+func WithCaseInsensitiveKeywords() ParseOption {
+	return func(o *parseOptions) {
+		o.caseInsensitiveKeywords = true
+	}
+}
+
+// WithInt64Range reports the integer literals that don't fit in an int64, e.g. `age gt 99999999999999999999`,
+// so IntegerLiteral.Int64 can't fail for a parsed expression.
+func WithInt64Range() ParseOption {
+	return func(o *parseOptions) {
+		o.int64Range = true
+	}
+}
+
+// WithSchema checks the parsed expression against schema, reporting the unknown fields, type mismatches and
+// operators not allowed, as Check does.
+func WithSchema(schema Schema) ParseOption {
+	return func(o *parseOptions) {
+		o.schema = schema
+	}
+}
+
+// WithMaxLength sets the maximum length of the input, in bytes, unlimited by default.
+// A longer input is not parsed, returning a nil Expression and an error with CodeInputTooLong.
+func WithMaxLength(maxLength int) ParseOption {
+	return func(o *parseOptions) {
+		o.maxLength = maxLength
+	}
+}
+
+// WithMaxDepth sets the maximum nesting depth of the expression, DefaultMaxDepth by default,
+// e.g. `not (age gt 18)` has a depth of 3: the not, the parentheses and the comparison.
+// Once exceeded, the parsing stops and an error with CodeMaxDepthExceeded is added.
+// A value lower or equal than 0 sets no limit, and a deep enough input can then overflow the stack.
+func WithMaxDepth(maxDepth int) ParseOption {
+	return func(o *parseOptions) {
+		o.maxDepth = maxDepth
+	}
+}
+
+// WithMaxNodes sets the maximum number of nodes of the expression, unlimited by default,
+// e.g. `age gt 18 and name eq 'John'` has 7 nodes, one per token but the parentheses.
+// Once exceeded, the parsing stops and an error with CodeTooManyNodes is added.
+func WithMaxNodes(maxNodes int) ParseOption {
+	return func(o *parseOptions) {
+		o.maxNodes = maxNodes
+	}
+}
+
+// WithMaxOrBranches sets the maximum number of branches joined with or in the whole expression, unlimited by default,
+// e.g. `a eq 1 or b eq 2 or c eq 3` has 3 branches.
+// Once exceeded, the parsing stops and an error with CodeTooManyOrBranches is added.
+func WithMaxOrBranches(maxOrBranches int) ParseOption {
+	return func(o *parseOptions) {
+		o.maxOrBranches = maxOrBranches
+	}
+}
+
+// WithMaxFields sets the maximum number of distinct fields of the expression, unlimited by default.
+// Once exceeded, the parsing stops and an error with CodeTooManyFields is added.
+func WithMaxFields(maxFields int) ParseOption {
+	return func(o *parseOptions) {
+		o.maxFields = maxFields
+	}
+}
+
+func newParseOptions(opts ...ParseOption) parseOptions {
+	o := parseOptions{maxErrors: DefaultMaxErrors, maxDepth: DefaultMaxDepth}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+func (o parseOptions) lexerOptions() []lexer.Option {
+	var opts []lexer.Option
+	if o.caseInsensitiveKeywords {
+		opts = append(opts, lexer.WithCaseInsensitiveKeywords())
+	}
+
+	return opts
+}
@@ -2,6 +2,8 @@ package goqrius
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 
 	"github.com/golaxo/goqrius/internal/lexer"
 	"github.com/golaxo/goqrius/internal/token"
@@ -31,14 +33,25 @@ var precedences = map[token.Type]int{
 
 type parser struct {
 	l         *lexer.Lexer
+	options   parseOptions
 	curToken  token.Token
 	peekToken token.Token
 	errors    []error
+	// depth is the number of open parentheses.
+	depth int
+	// stopped is set when the maximum number of errors, or a limit, is reached, and no more tokens are read.
+	stopped bool
+	// nesting is the depth of the expression being parsed, see WithMaxDepth.
+	nesting int
+	// nodes, orBranches and fields are counted to enforce the limits of the options.
+	nodes      int
+	orBranches int
+	fields     map[string]struct{}
 }
 
 // New creates a new parser based on a lexer.Lexer.
-func newParser(l *lexer.Lexer) *parser {
-	p := &parser{l: l}
+func newParser(l *lexer.Lexer, opts ...ParseOption) *parser {
+	p := &parser{l: l, options: newParseOptions(opts...), orBranches: 1, fields: make(map[string]struct{})}
 
 	// Read two tokens, so curToken and peekToken are both set
 	p.nextToken()
@@ -49,9 +62,73 @@ func newParser(l *lexer.Lexer) *parser {
 
 func (p *parser) Errors() []error { return p.errors }
 
+func (p *parser) addError(tok token.Token, code ErrorCode, message string) {
+	var suggestions []string
+
+	switch {
+	case code == CodeUnknownField:
+		suggestions = suggest(tok.Literal, p.options.fields)
+	case tok.Type == token.Ident:
+		suggestions = suggestKeywords(tok.Literal)
+	}
+
+	p.appendError(UnexpectedTokenError{
+		Token:       tok,
+		Code:        code,
+		Message:     message,
+		Suggestions: suggestions,
+	})
+}
+
+// appendError adds err, unless the maximum number of errors is reached.
+func (p *parser) appendError(err UnexpectedTokenError) {
+	if p.stopped {
+		return
+	}
+
+	if p.options.maxErrors > 0 && len(p.errors) == p.options.maxErrors {
+		p.errors = append(p.errors, UnexpectedTokenError{
+			Token:   err.Token,
+			Code:    CodeTooManyErrors,
+			Message: fmt.Sprintf("too many errors, stopped after %d", p.options.maxErrors),
+		})
+		p.stop()
+
+		return
+	}
+
+	p.errors = append(p.errors, err)
+}
+
+// exceed reports that a limit was exceeded at tok, and stops the parsing.
+func (p *parser) exceed(tok token.Token, code ErrorCode, message string) {
+	p.appendError(UnexpectedTokenError{Token: tok, Code: code, Message: message})
+	p.stop()
+}
+
+// stop finishes the parsing by pretending the end of the input was reached.
+func (p *parser) stop() {
+	p.stopped = true
+	eof := token.Token{Type: token.EOF, Position: p.peekToken.Position, End: p.peekToken.Position}
+	p.curToken = eof
+	p.peekToken = eof
+}
+
 func (p *parser) nextToken() {
+	if p.stopped {
+		return
+	}
+
 	p.curToken = p.peekToken
 	p.peekToken = p.l.NextToken()
+
+	switch p.peekToken.Type {
+	case token.Lparen, token.Rparen, token.EOF:
+	default:
+		if p.nodes++; p.options.maxNodes > 0 && p.nodes > p.options.maxNodes {
+			p.exceed(p.peekToken, CodeTooManyNodes, fmt.Sprintf("more than %d nodes", p.options.maxNodes))
+		}
+	}
 }
 
 func (p *parser) parse() Expression {
@@ -60,180 +137,210 @@ func (p *parser) parse() Expression {
 		return nil
 	}
 
-	expr := p.parseExpression(lowest)
-	if expr == nil {
-		return nil
-	}
+	expr := p.parseOperand(lowest)
+	p.checkOperand(expr)
 
-	if p.peekToken.Type == token.Illegal {
-		p.errors = append(p.errors, UnexpectedTokenError{
-			Token:   p.peekToken,
-			Message: fmt.Sprintf("illegal token %q", p.peekToken.Literal),
-		})
+	// after recovering from an error, keep parsing the remaining and/or clauses.
+	for p.peekToken.Type != token.EOF {
+		if !p.peekEndsOperand() {
+			p.skipLeftover(expr)
+		}
 
-		return expr
+		expr = p.parseInfix(expr, p.curToken, lowest)
 	}
 
-	if _, isValue := expr.(Value); isValue && p.peekToken.Type == token.EOF {
-		p.errors = append(p.errors, UnexpectedTokenError{
-			Token:   p.curToken,
-			Message: fmt.Sprintf("'%s' can not be used as a standalone expression", p.curToken.Literal),
-		})
-
-		return expr
+	if p.options.schema != nil {
+		for _, err := range check(expr, p.options.schema) {
+			p.appendError(err)
+		}
 	}
 
-	// consume remaining tokens and mark errors if any leftover meaningful tokens
-	for p.peekToken.Type != token.EOF {
-		{
-			_, isValue := expr.(Value)
+	return expr
+}
 
-			_, isIdentifier := expr.(*Identifier)
-			if isValue || isIdentifier {
-				p.errors = append(p.errors, UnexpectedTokenError{
-					Token:   p.peekToken,
-					Message: fmt.Sprintf("expected next token to be an operator, got %q", p.peekToken.Literal),
-				})
+// parseOperand parses an operand of a logical operator, recovering from any leftover token
+// by skipping until the next and/or/) boundary.
+func (p *parser) parseOperand(precedence int) Expression {
+	start := p.curToken.Position
 
-				return expr
-			}
-		}
+	expr := p.parseExpression(precedence)
 
+	// an unbalanced ')' doesn't invalidate the operand before it
+	for p.peekToken.Type == token.Rparen && p.depth == 0 {
+		p.addError(p.peekToken, CodeUnexpectedToken, fmt.Sprintf("unexpected token %q", p.peekToken.Literal))
 		p.nextToken()
+	}
 
-		if p.curToken.Type != token.EOF {
-			p.errors = append(p.errors, UnexpectedTokenError{
-				Token:   p.curToken,
-				Message: fmt.Sprintf("unexpected token %q", p.curToken.Literal),
-			})
-		}
+	if p.peekEndsOperand() {
+		return expr
 	}
 
-	return expr
+	p.skipLeftover(expr)
+
+	return p.bad(start)
 }
 
-//nolint:exhaustive,funlen,gocognit // refactor later
-func (p *parser) parseExpression(precedence int) Expression {
-	var leftExp Expression
+// skipLeftover reports the unexpected token after expr, and skips until the next and/or/) boundary.
+func (p *parser) skipLeftover(expr Expression) {
+	switch {
+	case p.peekToken.Type == token.Illegal:
+		p.addError(p.peekToken, CodeIllegalToken, fmt.Sprintf("illegal token %q", p.peekToken.Literal))
+	case isLeaf(expr):
+		p.addError(p.peekToken, CodeExpectedOperator,
+			fmt.Sprintf("expected next token to be an operator, got %q", p.peekToken.Literal))
+	default:
+		p.addError(p.peekToken, CodeUnexpectedToken, fmt.Sprintf("unexpected token %q", p.peekToken.Literal))
+	}
+
+	p.nextToken()
+	p.synchronize()
+}
 
+func (p *parser) parseExpression(precedence int) Expression {
 	leftToken := p.curToken
 
+	p.nesting++
+	defer func() { p.nesting-- }()
+
+	if p.options.maxDepth > 0 && p.nesting > p.options.maxDepth {
+		start := p.curToken.Position
+		p.exceed(p.curToken, CodeMaxDepthExceeded, fmt.Sprintf("expression nested more than %d levels", p.options.maxDepth))
+
+		return p.bad(start)
+	}
+
+	return p.parseInfix(p.parsePrefix(), leftToken, precedence)
+}
+
+//nolint:exhaustive // no need to check all the tokens.
+func (p *parser) parsePrefix() Expression {
 	switch p.curToken.Type {
 	case token.Ident:
-		leftExp = &Identifier{Value: p.curToken.Literal}
+		if len(p.options.fields) > 0 && !slices.Contains(p.options.fields, p.curToken.Literal) {
+			p.addError(p.curToken, CodeUnknownField, fmt.Sprintf("unknown field %q", p.curToken.Literal))
+		}
+
+		ident := &Identifier{Value: p.curToken.Literal, Span: spanOf(p.curToken)}
+
+		p.fields[p.curToken.Literal] = struct{}{}
+		if p.options.maxFields > 0 && len(p.fields) > p.options.maxFields {
+			p.exceed(p.curToken, CodeTooManyFields, fmt.Sprintf("more than %d distinct fields", p.options.maxFields))
+		}
+
+		return ident
 	case token.Int:
-		// bare int is invalid as an expression, record error but continue
-		leftExp = &IntegerLiteral{Value: p.curToken.Literal}
+		// bare int is invalid as an expression, it's checked by the caller
+		return &IntegerLiteral{Value: p.curToken.Literal, Span: spanOf(p.curToken)}
 	case token.String:
-		// bare string is invalid as an expression, record error but continue
-		leftExp = &StringLiteral{Value: p.curToken.Literal}
+		// bare string is invalid as an expression, it's checked by the caller
+		return &StringLiteral{Value: p.curToken.Literal, Span: spanOf(p.curToken)}
 	case token.Null:
-		// bare null is invalid
-		leftExp = &Null{}
-
+		// bare null is invalid, it's checked by the caller
+		return &Null{Span: spanOf(p.curToken)}
+	case token.True, token.False:
+		// bare boolean is invalid, it's checked by the caller
+		return &BooleanLiteral{Value: p.curToken.Type == token.True, Span: spanOf(p.curToken)}
 	case token.Not:
-		// prefix not
-		p.nextToken()
-		right := p.parseExpression(prefix)
-		// Disallow 'not' applied to a bare value
-		switch right.(type) {
-		case *IntegerLiteral, *StringLiteral, *Null:
-			p.errors = append(p.errors, UnexpectedTokenError{
-				Token:   p.curToken,
-				Message: "'not' can not be applied to a value",
-			})
-		case nil:
-			p.errors = append(p.errors, UnexpectedTokenError{
-				Token:   p.curToken,
-				Message: "missing expression after not",
-			})
-		}
-
-		leftExp = &NotExpr{Right: right}
+		return p.parseNot()
 	case token.Lparen:
-		// consume '(' and parse subexpression
-		p.nextToken()
+		return p.parseGroup()
+	case token.Illegal:
+		p.addError(p.curToken, CodeIllegalToken, fmt.Sprintf("illegal token %q", p.curToken.Literal))
+	case token.EOF:
+		p.addError(p.curToken, CodeMissingExpression, "missing expression")
 
-		inner := p.parseExpression(lowest)
-		p.expectPeek(token.Rparen)
-		// Disallow grouping a bare value as a full expression like (null)
-		switch inner.(type) {
-		case *IntegerLiteral, *StringLiteral, *Null:
-			p.errors = append(p.errors, UnexpectedTokenError{
-				Token:   p.curToken,
-				Message: "grouped value is not a valid expression",
-			})
-
-			return nil
-		}
+		return &MissingExpr{Span: Span{Start: p.curToken.Position, End: p.curToken.Position}}
+	default:
+		p.addError(p.curToken, CodeUnexpectedToken, fmt.Sprintf("unexpected token %q", p.curToken.Literal))
+	}
 
-		leftExp = inner
-	case token.Illegal:
-		p.errors = append(p.errors, UnexpectedTokenError{
-			Token:   p.curToken,
-			Message: fmt.Sprintf("illegal token %q", p.curToken.Literal),
-		})
+	start := p.curToken.Position
+	p.synchronize()
 
-		return nil
+	return p.bad(start)
+}
+
+func (p *parser) parseNot() Expression {
+	start := p.curToken.Position
+	if !p.expectOperand(MissingExpressionAfterNot) {
+		right := p.missing()
+
+		return &NotExpr{Right: right, Span: Span{Start: start, End: right.End()}}
+	}
+
+	right := p.parseExpression(prefix)
+	// Disallow 'not' applied to a bare value
+	switch right.(type) {
+	case *IntegerLiteral, *StringLiteral, *BooleanLiteral, *Null:
+		p.addError(p.curToken, CodeNotAppliedToValue, NotCannotBeAppliedToValue)
 	default:
-		p.errors = append(p.errors, UnexpectedTokenError{
-			Token:   p.curToken,
-			Message: fmt.Sprintf("no prefix parse function for %q", p.curToken.Literal),
-		})
+		p.checkOperand(right)
+	}
 
-		return nil
+	return &NotExpr{Right: right, Span: Span{Start: start, End: right.End()}}
+}
+
+func (p *parser) parseGroup() Expression {
+	start := p.curToken.Position
+	if !p.expectOperand("missing expression inside parentheses") {
+		missing := p.missing()
+		p.expectPeek(token.Rparen)
+
+		return missing
+	}
+
+	p.depth++
+	inner := p.parseOperand(lowest)
+
+	_, isIdentifier := inner.(*Identifier)
+	if isIdentifier {
+		p.checkOperand(inner)
+	}
+
+	p.depth--
+	p.expectPeek(token.Rparen)
+
+	// Disallow grouping a bare value as a full expression like (null)
+	switch inner.(type) {
+	case *IntegerLiteral, *StringLiteral, *BooleanLiteral, *Null:
+		p.addError(p.curToken, CodeGroupedValue, GroupedValueIsNotAnExpression)
+
+		return p.bad(start)
+	}
+
+	if isIdentifier {
+		return p.bad(start)
+	}
+
+	if p.curToken.Type == token.Rparen {
+		// the span of the group includes the parentheses.
+		setSpan(inner, Span{Start: start, End: p.curToken.End})
 	}
 
-	// Infix parsing loop
+	return inner
+}
+
+//nolint:exhaustive // no need to check all the tokens.
+func (p *parser) parseInfix(leftExp Expression, leftToken token.Token, precedence int) Expression {
 	for p.peekToken.Type != token.EOF && precedence < p.peekPrecedence() {
 		switch p.peekToken.Type {
 		case token.And:
+			p.checkOperand(leftExp)
 			p.nextToken() // move to 'and'
-			opPrec := p.curPrecedence()
-			p.nextToken() // move to the right prefix
-			right := p.parseExpression(opPrec)
-			leftExp = &AndExpr{Left: leftExp, Right: right}
+			right := p.parseRightOperand()
+			leftExp = &AndExpr{Left: leftExp, Right: right, Span: Span{Start: leftExp.Pos(), End: right.End()}}
 		case token.Or:
+			p.checkOperand(leftExp)
 			p.nextToken() // move to 'or'
-			opPrec := p.curPrecedence()
-			p.nextToken()
-			right := p.parseExpression(opPrec)
-			leftExp = &OrExpr{Left: leftExp, Right: right}
-		case token.Eq, token.NotEq, token.GreaterThan, token.GreaterThanOrEqual, token.LessThan, token.LessThanOrEqual:
-			// comparisons bind tighter than and/or
-			p.nextToken() // move to operator
-			operator := p.curToken.Type
-
-			// left must be an [Identifier]
-			ident, ok := leftExp.(*Identifier)
-			if !ok {
-				p.errors = append(p.errors, UnexpectedTokenError{
-					Token:   leftToken,
-					Message: LeftSideMustBeIdentifier,
-				})
-			}
-
-			// parse right value
-			p.nextToken()
-			val := p.parseValue()
-
-			// validate null with comparison
-			if _, isNull := val.(*Null); isNull {
-				switch operator {
-				case token.GreaterThan, token.GreaterThanOrEqual, token.LessThan, token.LessThanOrEqual:
-					p.errors = append(p.errors, UnexpectedTokenError{
-						Token:   p.curToken,
-						Message: NullCannotBeUsedWithComparison,
-					})
-				}
-			}
 
-			if ident == nil {
-				// fabricate to proceed
-				ident = &Identifier{Value: ""}
+			if p.orBranches++; p.options.maxOrBranches > 0 && p.orBranches > p.options.maxOrBranches {
+				p.exceed(p.curToken, CodeTooManyOrBranches, fmt.Sprintf("more than %d or branches", p.options.maxOrBranches))
 			}
 
-			leftExp = &FilterExpr{Left: ident, Operator: FilterOperator(operator), Right: val}
+			right := p.parseRightOperand()
+			leftExp = &OrExpr{Left: leftExp, Right: right, Span: Span{Start: leftExp.Pos(), End: right.End()}}
+		case token.Eq, token.NotEq, token.GreaterThan, token.GreaterThanOrEqual, token.LessThan, token.LessThanOrEqual:
+			leftExp = p.parseComparison(leftExp, leftToken)
 		default:
 			return leftExp
 		}
@@ -242,55 +349,175 @@ func (p *parser) parseExpression(precedence int) Expression {
 	return leftExp
 }
 
+// parseRightOperand parses the right side of the current and/or operator.
+func (p *parser) parseRightOperand() Expression {
+	opPrec := p.curPrecedence()
+	if !p.expectOperand(fmt.Sprintf("missing expression after %s", p.curToken.Literal)) {
+		return p.missing()
+	}
+
+	right := p.parseOperand(opPrec)
+	p.checkOperand(right)
+
+	return right
+}
+
+func (p *parser) parseComparison(leftExp Expression, leftToken token.Token) Expression {
+	// comparisons bind tighter than and/or
+	p.nextToken() // move to operator
+	operator := p.curToken.Type
+
+	// left must be an [Identifier]
+	ident, ok := leftExp.(*Identifier)
+	if !ok {
+		p.addError(leftToken, CodeLeftSideMustBeIdentifier, LeftSideMustBeIdentifier)
+	}
+
+	// parse right value
+	var val Value
+	if p.expectOperand(fmt.Sprintf("missing value after %s", operator)) {
+		val = p.parseValue()
+	} else {
+		val = p.missing()
+	}
+
+	// validate null with comparison
+	if _, isNull := val.(*Null); isNull {
+		switch operator {
+		case token.GreaterThan, token.GreaterThanOrEqual, token.LessThan, token.LessThanOrEqual:
+			p.addError(p.curToken, CodeNullWithComparison, NullCannotBeUsedWithComparison)
+		}
+	}
+
+	if ident == nil {
+		return &BadExpr{Span: Span{Start: leftExp.Pos(), End: val.End()}}
+	}
+
+	return &FilterExpr{
+		Left:     ident,
+		Operator: FilterOperator(operator),
+		Right:    val,
+		Span:     Span{Start: ident.Pos(), End: val.End()},
+	}
+}
+
 //nolint:exhaustive // no need to check all the tokens.
 func (p *parser) parseValue() Value {
 	switch p.curToken.Type {
 	case token.Int:
-		return &IntegerLiteral{Value: p.curToken.Literal}
+		if p.options.int64Range {
+			if _, err := strconv.ParseInt(p.curToken.Literal, 10, 64); err != nil {
+				p.addError(p.curToken, CodeIntegerOutOfRange, fmt.Sprintf("integer %s out of the int64 range", p.curToken.Literal))
+			}
+		}
+
+		return &IntegerLiteral{Value: p.curToken.Literal, Span: spanOf(p.curToken)}
 	case token.String:
-		return &StringLiteral{Value: p.curToken.Literal}
+		return &StringLiteral{Value: p.curToken.Literal, Span: spanOf(p.curToken)}
 	case token.Null:
-		return &Null{}
+		return &Null{Span: spanOf(p.curToken)}
+	case token.True, token.False:
+		return &BooleanLiteral{Value: p.curToken.Type == token.True, Span: spanOf(p.curToken)}
 	case token.Ident:
-		p.errors = append(p.errors, UnexpectedTokenError{
-			Token:   p.curToken,
-			Message: "identifier can not be used as value",
-		})
+		p.addError(p.curToken, CodeIdentifierAsValue, IdentifierCannotBeUsedAsValue)
 
-		return nil
+		return &BadExpr{Span: spanOf(p.curToken)}
 	case token.Lparen:
 		// value cannot be a grouped expression (e.g., (not null)) per tests
-		p.nextToken()
-		startToken := p.curToken
+		start := p.curToken.Position
+		startToken := p.peekToken
+
+		if !p.expectOperand("missing value inside parentheses") {
+			missing := p.missing()
+			p.expectPeek(token.Rparen)
+
+			return missing
+		}
 
-		inner := p.parseExpression(lowest)
+		p.depth++
+		inner := p.parseOperand(lowest)
+		p.depth--
 		p.expectPeek(token.Rparen)
 
+		if isPlaceholder(inner) {
+			return p.bad(start)
+		}
+
 		if v, ok := inner.(Value); ok {
-			p.errors = append(p.errors, UnexpectedTokenError{
-				Token:   p.curToken,
-				Message: "invalid value expression",
-			})
+			p.addError(p.curToken, CodeInvalidValue, InvalidValueExpression)
 
 			return v
 		}
 
-		p.errors = append(p.errors, UnexpectedTokenError{
-			Token:   startToken,
-			Message: "right side of comparison must be a value",
-		})
+		p.addError(startToken, CodeRightSideMustBeValue, RightSideMustBeValue)
 
-		return nil
+		return p.bad(start)
 	default:
-		p.errors = append(p.errors, UnexpectedTokenError{
-			Token:   p.curToken,
-			Message: fmt.Sprintf("invalid value token %q", p.curToken.Literal),
-		})
+		p.addError(p.curToken, CodeInvalidValue, fmt.Sprintf("invalid value token %q", p.curToken.Literal))
 
-		return nil
+		start := p.curToken.Position
+		p.synchronize()
+
+		return p.bad(start)
+	}
+}
+
+// checkOperand reports a bare identifier or value used as an operand of a logical operator,
+// as they can only be used in a comparison.
+// When a leftover token follows the operand, that token is reported instead.
+func (p *parser) checkOperand(expr Expression) {
+	if !isLeaf(expr) || !p.peekEndsOperand() {
+		return
+	}
+
+	p.addError(p.curToken, CodeStandaloneValue,
+		fmt.Sprintf("'%s' can not be used as a standalone expression", p.curToken.Literal))
+}
+
+// expectOperand moves to the next token if it can start an operand,
+// otherwise it reports the missing operand with the message.
+func (p *parser) expectOperand(message string) bool {
+	switch p.peekToken.Type {
+	case token.And, token.Or, token.Rparen, token.EOF:
+		p.addError(p.peekToken, CodeMissingExpression, message)
+
+		return false
+	default:
+		p.nextToken()
+
+		return true
 	}
 }
 
+// peekEndsOperand reports whether the next token is a boundary of an operand: and, or, ) or the end of the input.
+func (p *parser) peekEndsOperand() bool {
+	switch p.peekToken.Type {
+	case token.And, token.Or, token.EOF:
+		return true
+	case token.Rparen:
+		return p.depth > 0
+	default:
+		return false
+	}
+}
+
+// synchronize skips tokens until the next and/or/) boundary, so the parsing can continue after an error.
+func (p *parser) synchronize() {
+	for !p.peekEndsOperand() {
+		p.nextToken()
+	}
+}
+
+// bad returns a BadExpr from the start position until the current token.
+func (p *parser) bad(start int) *BadExpr {
+	return &BadExpr{Span: Span{Start: start, End: max(start, p.curToken.End)}}
+}
+
+// missing returns a MissingExpr placed at the next token.
+func (p *parser) missing() *MissingExpr {
+	return &MissingExpr{Span: Span{Start: p.peekToken.Position, End: p.peekToken.Position}}
+}
+
 func (p *parser) expectPeek(t token.Type) {
 	if p.peekToken.Type == t {
 		p.nextToken()
@@ -298,10 +525,8 @@ func (p *parser) expectPeek(t token.Type) {
 		return
 	}
 
-	p.errors = append(p.errors, UnexpectedTokenError{
-		Token:   p.peekToken,
-		Message: fmt.Sprintf("expected next token to be %q, got %q", t, p.peekToken.Literal),
-	})
+	p.addError(p.peekToken, CodeExpectedToken,
+		fmt.Sprintf("expected next token to be %q, got %q", t, p.peekToken.Literal))
 }
 
 func (p *parser) peekPrecedence() int {
@@ -319,3 +544,45 @@ func (p *parser) curPrecedence() int {
 
 	return lowest
 }
+
+// setSpan sets the span of a logical, filter or placeholder expression.
+func setSpan(expr Expression, span Span) {
+	switch e := expr.(type) {
+	case *AndExpr:
+		e.Span = span
+	case *OrExpr:
+		e.Span = span
+	case *NotExpr:
+		e.Span = span
+	case *FilterExpr:
+		e.Span = span
+	case *BadExpr:
+		e.Span = span
+	case *MissingExpr:
+		e.Span = span
+	}
+}
+
+func spanOf(tok token.Token) Span {
+	return Span{Start: tok.Position, End: tok.End}
+}
+
+// isLeaf reports whether the expression is an identifier or a value, which can only be used in a comparison.
+func isLeaf(expr Expression) bool {
+	switch expr.(type) {
+	case *Identifier, *IntegerLiteral, *StringLiteral, *BooleanLiteral, *Null:
+		return true
+	default:
+		return false
+	}
+}
+
+// isPlaceholder reports whether the expression is a BadExpr or a MissingExpr.
+func isPlaceholder(expr Expression) bool {
+	switch expr.(type) {
+	case *BadExpr, *MissingExpr:
+		return true
+	default:
+		return false
+	}
+}
@@ -1,6 +1,8 @@
 package goqrius
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/golaxo/goqrius/internal/lexer"
@@ -22,6 +24,10 @@ func TestParseExpressions(t *testing.T) {
 			input:          "name ne null",
 			expectedString: "(name ne null)",
 		},
+		"ident eq boolean": {
+			input:          "active eq true or deleted ne false",
+			expectedString: "((active eq true) or (deleted ne false))",
+		},
 		"ident eq string": {
 			input:          "name eq 'john'",
 			expectedString: "(name eq 'john')",
@@ -331,14 +337,6 @@ func TestParseErrors(t *testing.T) {
 					},
 					Message: "invalid value token \"not\"",
 				},
-				UnexpectedTokenError{
-					Token: token.Token{
-						Type:     token.Null,
-						Literal:  "null",
-						Position: 12,
-					},
-					Message: "unexpected token \"null\"",
-				},
 			},
 		},
 		"name eq (not null)": {
@@ -413,3 +411,379 @@ func TestParseErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestParseWithInt64Range(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input   string
+		wantErr bool
+	}{
+		"max int64":      {input: "id eq 9223372036854775807"},
+		"over max int64": {input: "id eq 9223372036854775808", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Parse(tt.input); err != nil {
+				t.Fatalf("unexpected error without the option: %v", err)
+			}
+
+			_, err := Parse(tt.input, WithInt64Range())
+			if errors.Is(err, ErrIntegerOutOfRange) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseRecovery(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input          string
+		expectedErrors []string
+	}{
+		"independent errors in and/or clauses": {
+			input: "name eqq 'John' and 1 gt 2 or age gt null",
+			expectedErrors: []string{
+				"expected next token to be an operator, got \"eqq\", at position 5",
+				"left side of comparison must be an identifier, at position 20",
+				"'null' can not be used with comparison operator, at position 37",
+			},
+		},
+		"recover inside parentheses": {
+			input: "a eq 1 or (b eq 2 foo) and c eq 3 bar",
+			expectedErrors: []string{
+				"unexpected token \"foo\", at position 18",
+				"unexpected token \"bar\", at position 34",
+			},
+		},
+		"unbalanced closing paren": {
+			input: "a eq 1) and b eq value",
+			expectedErrors: []string{
+				"unexpected token \")\", at position 6",
+				"identifier can not be used as value, at position 17",
+			},
+		},
+		"missing operand": {
+			input: "a eq 1 and or b eq 2",
+			expectedErrors: []string{
+				"missing expression after and, at position 11",
+			},
+		},
+		"missing value": {
+			input: "a eq and b eq 2",
+			expectedErrors: []string{
+				"missing value after eq, at position 5",
+			},
+		},
+		"missing expression after not": {
+			input: "a eq 1 and not",
+			expectedErrors: []string{
+				"missing expression after not, at position 14",
+			},
+		},
+		"bare identifier operand": {
+			input: "a eq 1 and b",
+			expectedErrors: []string{
+				"'b' can not be used as a standalone expression, at position 11",
+			},
+		},
+		"bare identifier in group": {
+			input: "(a)",
+			expectedErrors: []string{
+				"'a' can not be used as a standalone expression, at position 1",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			p := newParser(lexer.New(tt.input))
+			_ = p.parse()
+
+			if len(p.Errors()) != len(tt.expectedErrors) {
+				t.Fatalf("expected %d errors, got %d: %v", len(tt.expectedErrors), len(p.Errors()), p.Errors())
+			}
+
+			for i, err := range p.Errors() {
+				if err.Error() != tt.expectedErrors[i] {
+					t.Fatalf("expected error at [%d]: %v, got: %v", i, tt.expectedErrors[i], err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseMaxErrors(t *testing.T) {
+	t.Parallel()
+
+	input := "1 eq 1 and 2 eq 2 and 3 eq 3 and 4 eq 4"
+
+	tests := map[string]struct {
+		opts             []ParseOption
+		expectedErrors   int
+		expectedLastCode ErrorCode
+	}{
+		"below the default": {
+			expectedErrors:   4,
+			expectedLastCode: CodeLeftSideMustBeIdentifier,
+		},
+		"capped": {
+			opts:             []ParseOption{WithMaxErrors(2)},
+			expectedErrors:   3,
+			expectedLastCode: CodeTooManyErrors,
+		},
+		"unlimited": {
+			opts:             []ParseOption{WithMaxErrors(0)},
+			expectedErrors:   4,
+			expectedLastCode: CodeLeftSideMustBeIdentifier,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			p := newParser(lexer.New(input), tt.opts...)
+			_ = p.parse()
+
+			if len(p.Errors()) != tt.expectedErrors {
+				t.Fatalf("expected %d errors, got %d: %v", tt.expectedErrors, len(p.Errors()), p.Errors())
+			}
+
+			var ute UnexpectedTokenError
+
+			last := p.Errors()[len(p.Errors())-1]
+			if !errors.As(last, &ute) || ute.Code != tt.expectedLastCode {
+				t.Fatalf("expected last error to be %q, got %v", tt.expectedLastCode, last)
+			}
+		})
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	t.Parallel()
+
+	deepGroups := strings.Repeat("(", 100000) + "a eq 1" + strings.Repeat(")", 100000)
+	deepNots := strings.Repeat("not ", 100000) + "a eq 1"
+
+	tests := map[string]struct {
+		input            string
+		opts             []ParseOption
+		expectedCode     ErrorCode
+		expectedPosition int
+	}{
+		"within the limits": {
+			input: "not (a eq 1 or b eq 2) and c eq 3",
+			opts: []ParseOption{
+				WithMaxLength(33), WithMaxDepth(4), WithMaxNodes(12), WithMaxOrBranches(2), WithMaxFields(3),
+			},
+		},
+		"input too long": {
+			input: "a eq 1 and b eq 2", opts: []ParseOption{WithMaxLength(10)},
+			expectedCode: CodeInputTooLong, expectedPosition: 10,
+		},
+		"deep groups by default": {
+			input: deepGroups, expectedCode: CodeMaxDepthExceeded, expectedPosition: DefaultMaxDepth,
+		},
+		"deep nots by default": {
+			input: deepNots, expectedCode: CodeMaxDepthExceeded, expectedPosition: 4 * DefaultMaxDepth,
+		},
+		"max depth": {
+			input: "not (a eq 1)", opts: []ParseOption{WithMaxDepth(2)},
+			expectedCode: CodeMaxDepthExceeded, expectedPosition: 5,
+		},
+		"unlimited depth": {
+			input: strings.Repeat("(", 1000) + "a eq 1" + strings.Repeat(")", 1000), opts: []ParseOption{WithMaxDepth(0)},
+		},
+		"too many nodes": {
+			input: "a eq 1 and b eq 2", opts: []ParseOption{WithMaxNodes(6)},
+			expectedCode: CodeTooManyNodes, expectedPosition: 16,
+		},
+		"too many or branches": {
+			input: "a eq 1 or a eq 2 or a eq 3", opts: []ParseOption{WithMaxOrBranches(2)},
+			expectedCode: CodeTooManyOrBranches, expectedPosition: 17,
+		},
+		"or branches in groups": {
+			input: "(a eq 1 or a eq 2) and (a eq 3 or a eq 4)", opts: []ParseOption{WithMaxOrBranches(2)},
+			expectedCode: CodeTooManyOrBranches, expectedPosition: 31,
+		},
+		"too many fields": {
+			input: "a eq 1 and a eq 2 and b eq 3 and c eq 4", opts: []ParseOption{WithMaxFields(2)},
+			expectedCode: CodeTooManyFields, expectedPosition: 33,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(tt.input, tt.opts...)
+			if tt.expectedCode == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			var pe ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("expected a ParseError, got %v", err)
+			}
+
+			// the parsing stops once a limit is exceeded.
+			var ute UnexpectedTokenError
+
+			last := pe.Errors()[len(pe.Errors())-1]
+			if !errors.As(last, &ute) || ute.Code != tt.expectedCode || ute.Token.Position != tt.expectedPosition {
+				t.Fatalf("expected %q at position %d, got %v", tt.expectedCode, tt.expectedPosition, last)
+			}
+		})
+	}
+}
+
+func TestParsePartialTree(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input          string
+		expectedString string
+	}{
+		"missing right operand": {
+			input:          "a eq 1 and",
+			expectedString: "((a eq 1) and <missing>)",
+		},
+		"missing value": {
+			input:          "a eq and b eq 2",
+			expectedString: "((a eq <missing>) and (b eq 2))",
+		},
+		"missing expression after not": {
+			input:          "not",
+			expectedString: "(not <missing>)",
+		},
+		"invalid left side": {
+			input:          "1 gt 2 or a eq 1",
+			expectedString: "(<bad> or (a eq 1))",
+		},
+		"leftover tokens": {
+			input:          "a eq 1 foo bar and b eq 2",
+			expectedString: "(<bad> and (b eq 2))",
+		},
+		"invalid value": {
+			input:          "name eq not null",
+			expectedString: "(name eq <bad>)",
+		},
+		"identifier as value": {
+			input:          "name eq value",
+			expectedString: "(name eq <bad>)",
+		},
+		"empty parentheses": {
+			input:          "() or a eq 1",
+			expectedString: "(<missing> or (a eq 1))",
+		},
+		"illegal token": {
+			input:          "@ eq 1 and a eq 1",
+			expectedString: "(<bad> and (a eq 1))",
+		},
+		"unbalanced closing paren": {
+			input:          "a eq 1 and )",
+			expectedString: "((a eq 1) and <missing>)",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := Parse(tt.input)
+			if err == nil {
+				t.Fatalf("expected error, got none")
+			}
+
+			assertWellFormed(t, expr, len(tt.input))
+
+			if got := expr.String(); got != tt.expectedString {
+				t.Fatalf("unexpected AST string. expected=%q got=%q", tt.expectedString, got)
+			}
+		})
+	}
+}
+
+func TestParseSpans(t *testing.T) {
+	t.Parallel()
+
+	input := "not name eq 'John' and (age gt 18 or age eq null)"
+
+	expr, err := Parse(input)
+	if err != nil {
+		t.Fatalf("err not expected; error=%v", err)
+	}
+
+	assertWellFormed(t, expr, len(input))
+
+	and, ok := expr.(*AndExpr)
+	if !ok {
+		t.Fatalf("expected *AndExpr, got %T", expr)
+	}
+
+	tests := map[string]struct {
+		node     Node
+		expected string
+	}{
+		"and":        {node: and, expected: input},
+		"not":        {node: and.Left, expected: "not name eq 'John'"},
+		"string":     {node: and.Left.(*NotExpr).Right.(*FilterExpr).Right, expected: "'John'"},
+		"or":         {node: and.Right, expected: "(age gt 18 or age eq null)"},
+		"comparison": {node: and.Right.(*OrExpr).Left, expected: "age gt 18"},
+		"identifier": {node: and.Right.(*OrExpr).Right.(*FilterExpr).Left, expected: "age"},
+	}
+
+	for name, tt := range tests {
+		if got := input[tt.node.Pos():tt.node.End()]; got != tt.expected {
+			t.Fatalf("unexpected span for %s. expected=%q got=%q", name, tt.expected, got)
+		}
+	}
+
+	nested := MustParse("((a eq 1))")
+	if nested.Pos() != 0 || nested.End() != 10 {
+		t.Fatalf("expected the nested groups to span the whole input, got [%d,%d)", nested.Pos(), nested.End())
+	}
+}
+
+// assertWellFormed checks that the tree has no nil nodes and that every span is within the input.
+func assertWellFormed(t *testing.T, expr Expression, length int) {
+	t.Helper()
+
+	if expr == nil {
+		t.Fatalf("unexpected nil node")
+	}
+
+	if expr.Pos() < 0 || expr.Pos() > expr.End() || expr.End() > length {
+		t.Fatalf("invalid span [%d,%d) for %s", expr.Pos(), expr.End(), expr)
+	}
+
+	switch e := expr.(type) {
+	case *AndExpr:
+		assertWellFormed(t, e.Left, length)
+		assertWellFormed(t, e.Right, length)
+	case *OrExpr:
+		assertWellFormed(t, e.Left, length)
+		assertWellFormed(t, e.Right, length)
+	case *NotExpr:
+		assertWellFormed(t, e.Right, length)
+	case *FilterExpr:
+		if e.Left == nil || e.Right == nil {
+			t.Fatalf("unexpected nil node in %s", e)
+		}
+
+		assertWellFormed(t, e.Left, length)
+		assertWellFormed(t, e.Right, length)
+	}
+}
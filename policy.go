@@ -0,0 +1,132 @@
+package goqrius
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	_ error = new(PolicyError)
+	_ error = new(Violation)
+)
+
+type (
+	// Policy is an allowlist of the fields that can be used in a filter expression, with the operators allowed for each
+	// one, e.g. `Policy{"name": nil, "email": {Eq}}` allows any operator with name, only eq with email,
+	// and no other field. See Policy.Enforce.
+	//
+	// Only a nil list allows any operator, an empty one, e.g. `Policy{"email": {}}`, allows none,
+	// so a list that ends up empty doesn't open the field to every operator.
+	Policy map[string][]FilterOperator
+
+	// Violation describes a field or an operator of the filter expression that is not allowed by a Policy.
+	Violation struct {
+		// Code is CodeFieldNotAllowed or CodeOperatorNotAllowed.
+		Code  ErrorCode
+		Field string
+		// Operator is the operator not allowed, empty for a field not allowed.
+		Operator FilterOperator
+		// Span is the position of the field, or of the comparison for an operator not allowed.
+		Span Span
+	}
+
+	// PolicyError groups all the violations of a Policy found in a filter expression.
+	PolicyError struct {
+		Violations []Violation
+	}
+)
+
+// Enforce returns a PolicyError listing every violation of the policy in expr, or nil if there are none.
+// It's meant to be called before the expression reaches the data layer, as the fields are usually columns.
+func (p Policy) Enforce(expr Expression) error {
+	violations := p.Violations(expr)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return PolicyError{Violations: violations}
+}
+
+// Violations returns the fields and operators of expr not allowed by the policy, in the order they appear.
+func (p Policy) Violations(expr Expression) []Violation {
+	var violations []Violation
+
+	Inspect(expr, func(e Expression) bool {
+		switch x := e.(type) {
+		case *FilterExpr:
+			if x.Left == nil {
+				return true
+			}
+
+			operators, ok := p[x.Left.Value]
+			switch {
+			case !ok:
+				violations = append(violations, Violation{Code: CodeFieldNotAllowed, Field: x.Left.Value, Span: x.Left.Span})
+			case operators != nil && !slices.Contains(operators, x.Operator):
+				violations = append(violations, Violation{
+					Code: CodeOperatorNotAllowed, Field: x.Left.Value, Operator: x.Operator, Span: x.Span,
+				})
+			}
+
+			return false
+		case *Identifier:
+			// a bare identifier, in a partial tree.
+			if _, ok := p[x.Value]; !ok {
+				violations = append(violations, Violation{Code: CodeFieldNotAllowed, Field: x.Value, Span: x.Span})
+			}
+		}
+
+		return true
+	})
+
+	return violations
+}
+
+// token returns the text of the field or operator not allowed.
+func (v Violation) token() string {
+	if v.Code == CodeOperatorNotAllowed {
+		return string(v.Operator)
+	}
+
+	return v.Field
+}
+
+func (v Violation) Error() string {
+	return fmt.Sprintf("%s, at position %d", v.Message(), v.Span.Start)
+}
+
+// Message describes the violation, without its position.
+func (v Violation) Message() string {
+	if v.Code == CodeOperatorNotAllowed {
+		return fmt.Sprintf("operator %s is not allowed for the field %q", v.Operator, v.Field)
+	}
+
+	return fmt.Sprintf("field %q is not allowed", v.Field)
+}
+
+// Is reports whether target is the sentinel error of the violation's Code.
+func (v Violation) Is(target error) bool {
+	sentinel, ok := sentinels[v.Code]
+
+	return ok && sentinel == target
+}
+
+func (p PolicyError) Error() string {
+	messages := make([]string, len(p.Violations))
+	for i, v := range p.Violations {
+		messages[i] = v.Error()
+	}
+
+	return strings.Join(messages, ",")
+}
+
+// Unwrap allows errors.Is and errors.As to inspect the individual violations.
+func (p PolicyError) Unwrap() []error {
+	errs := make([]error, len(p.Violations))
+	for i, v := range p.Violations {
+		errs[i] = v
+	}
+
+	return errs
+}
@@ -0,0 +1,126 @@
+package goqrius
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPolicyViolations(t *testing.T) {
+	t.Parallel()
+
+	policy := Policy{
+		"name":  nil,
+		"age":   {GreaterThan, LessThan},
+		"email": {Eq},
+		"token": {},
+	}
+
+	tests := map[string]struct {
+		expr     Expression
+		expected []Violation
+	}{
+		"allowed": {
+			expr: MustParse("name ne 'John' and (age gt 18 or email eq 'john@example.com')"),
+		},
+		"nil expression": {},
+		"field not allowed": {
+			expr: MustParse("name eq 'John' and password eq 'secret'"),
+			expected: []Violation{
+				{Code: CodeFieldNotAllowed, Field: "password", Span: Span{Start: 19, End: 27}},
+			},
+		},
+		"operator not allowed": {
+			expr: MustParse("email ne 'a'"),
+			expected: []Violation{
+				{Code: CodeOperatorNotAllowed, Field: "email", Operator: NotEq, Span: Span{Start: 0, End: 12}},
+			},
+		},
+		"empty operators": {
+			expr: MustParse("token eq 'a' or token gt 'a'"),
+			expected: []Violation{
+				{Code: CodeOperatorNotAllowed, Field: "token", Operator: Eq, Span: Span{Start: 0, End: 12}},
+				{Code: CodeOperatorNotAllowed, Field: "token", Operator: GreaterThan, Span: Span{Start: 16, End: 28}},
+			},
+		},
+		"every violation in order": {
+			expr: MustParse("not (age eq 18 or salary gt 1000)"),
+			expected: []Violation{
+				{Code: CodeOperatorNotAllowed, Field: "age", Operator: Eq, Span: Span{Start: 5, End: 14}},
+				{Code: CodeFieldNotAllowed, Field: "salary", Span: Span{Start: 18, End: 24}},
+			},
+		},
+		"bare identifier": {
+			expr: &NotExpr{Right: &Identifier{Value: "password"}},
+			expected: []Violation{
+				{Code: CodeFieldNotAllowed, Field: "password"},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := policy.Violations(tt.expr); !reflect.DeepEqual(got, tt.expected) {
+				t.Fatalf("unexpected violations.\nexpected=%v\ngot=%v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPolicyEnforce(t *testing.T) {
+	t.Parallel()
+
+	policy := Policy{"email": {Eq}}
+
+	if err := policy.Enforce(MustParse("email eq 'a'")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := policy.Enforce(MustParse("email gt 'a' or name eq 'John'"))
+	if !errors.Is(err, ErrOperatorNotAllowed) || !errors.Is(err, ErrFieldNotAllowed) {
+		t.Fatalf("expected both violations, got %v", err)
+	}
+
+	expected := `operator gt is not allowed for the field "email", at position 0,field "name" is not allowed, at position 16`
+	if err.Error() != expected {
+		t.Fatalf("unexpected message.\nexpected: %s\ngot:      %s", expected, err.Error())
+	}
+
+	var v Violation
+	if !errors.As(err, &v) || v.Field != "email" {
+		t.Fatalf("expected the first violation, got %v", v)
+	}
+
+	if err = (Policy{"email": {}}).Enforce(MustParse("email gt 'a'")); !errors.Is(err, ErrOperatorNotAllowed) {
+		t.Fatalf("expected an empty list of operators to allow none, got %v", err)
+	}
+}
+
+func TestPolicyProblem(t *testing.T) {
+	t.Parallel()
+
+	err := Policy{"email": {Eq}}.Enforce(MustParse("email gt 'a' or name eq 'John'"))
+
+	got, jsonErr := json.Marshal(NewProblem(err))
+	if jsonErr != nil {
+		t.Fatalf("unexpected error: %v", jsonErr)
+	}
+
+	expected := `{"type":"about:blank","title":"Bad Request","status":400,` +
+		`"detail":"2 errors found in the filter expression","errors":[` +
+		`{"code":"operator_not_allowed","message":"operator gt is not allowed for the field \"email\"",` +
+		`"position":0,"token":"gt"},` +
+		`{"code":"field_not_allowed","message":"field \"name\" is not allowed","position":16,"token":"name"}]}`
+	if string(got) != expected {
+		t.Fatalf("unexpected problem.\nexpected: %s\ngot:      %s", expected, got)
+	}
+
+	odata := NewODataError(err)
+	if odata.Error.Details[0].Code != CodeOperatorNotAllowed || odata.Error.Details[0].Target != "email" ||
+		odata.Error.Details[1].Code != CodeFieldNotAllowed || odata.Error.Details[1].Target != "name" {
+		t.Fatalf("unexpected OData details: %+v", odata.Error.Details)
+	}
+}
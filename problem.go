@@ -0,0 +1,207 @@
+package goqrius
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const (
+	// ProblemContentType is the media type of a Problem, as defined in RFC 7807.
+	ProblemContentType = "application/problem+json"
+	// ODataTarget is the target reported in an ODataError, the query option holding the filter expression.
+	ODataTarget = "$filter"
+)
+
+type (
+	// Problem is an RFC 7807 problem details document describing why a filter expression is not valid.
+	Problem struct {
+		Type     string `json:"type"`
+		Title    string `json:"title"`
+		Status   int    `json:"status"`
+		Detail   string `json:"detail,omitempty"`
+		Instance string `json:"instance,omitempty"`
+		// Errors is an extension member listing every error found in the filter expression.
+		Errors []ProblemError `json:"errors,omitempty"`
+	}
+
+	// ProblemError describes a single error of the filter expression.
+	ProblemError struct {
+		Code    ErrorCode `json:"code,omitempty"`
+		Message string    `json:"message"`
+		// Position of the error in the filter expression, if known.
+		Position    *int     `json:"position,omitempty"`
+		Token       string   `json:"token,omitempty"`
+		Suggestions []string `json:"suggestions,omitempty"`
+	}
+
+	// ProblemOption configures the Problem created by NewProblem.
+	ProblemOption func(*Problem)
+
+	// ODataError is the OData JSON error response describing why a filter expression is not valid.
+	ODataError struct {
+		Error ODataErrorBody `json:"error"`
+	}
+
+	// ODataErrorBody is the body of an ODataError.
+	ODataErrorBody struct {
+		Code    ErrorCode          `json:"code"`
+		Message string             `json:"message"`
+		Target  string             `json:"target,omitempty"`
+		Details []ODataErrorDetail `json:"details,omitempty"`
+	}
+
+	// ODataErrorDetail describes a single error of the filter expression.
+	ODataErrorDetail struct {
+		Code    ErrorCode `json:"code,omitempty"`
+		Message string    `json:"message"`
+		// Target is the field in error, if known.
+		Target string `json:"target,omitempty"`
+	}
+)
+
+// WithProblemType sets the URI reference that identifies the problem type, "about:blank" by default.
+func WithProblemType(uri string) ProblemOption {
+	return func(p *Problem) {
+		p.Type = uri
+	}
+}
+
+// WithProblemInstance sets the URI reference that identifies the specific occurrence of the problem.
+func WithProblemInstance(uri string) ProblemOption {
+	return func(p *Problem) {
+		p.Instance = uri
+	}
+}
+
+// NewProblem converts an error returned by Parse, Check or Policy.Enforce into an RFC 7807 Problem with status 400.
+func NewProblem(err error, opts ...ProblemOption) *Problem {
+	errs := unwrapParseError(err)
+
+	p := &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusBadRequest),
+		Status: http.StatusBadRequest,
+		Detail: detail(errs),
+		Errors: make([]ProblemError, len(errs)),
+	}
+
+	for i, e := range errs {
+		pe := ProblemError{Message: e.Error()}
+
+		var (
+			ute UnexpectedTokenError
+			v   Violation
+		)
+
+		switch {
+		case errors.As(e, &ute):
+			position := ute.Token.Position
+			pe = ProblemError{
+				Code:        ute.Code,
+				Message:     ute.Message,
+				Position:    &position,
+				Token:       ute.Token.Literal,
+				Suggestions: ute.Suggestions,
+			}
+		case errors.As(e, &v):
+			position := v.Span.Start
+			pe = ProblemError{Code: v.Code, Message: v.Message(), Position: &position, Token: v.token()}
+		}
+
+		p.Errors[i] = pe
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// WriteProblem writes the Problem of err as an application/problem+json response.
+func WriteProblem(w http.ResponseWriter, err error, opts ...ProblemOption) error {
+	p := NewProblem(err, opts...)
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+
+	return json.NewEncoder(w).Encode(p)
+}
+
+// NewODataError converts an error returned by Parse, Check or Policy.Enforce into an OData JSON error response.
+func NewODataError(err error) *ODataError {
+	errs := unwrapParseError(err)
+
+	oe := &ODataError{
+		Error: ODataErrorBody{
+			Code:    CodeInvalidFilter,
+			Message: detail(errs),
+			Target:  ODataTarget,
+			Details: make([]ODataErrorDetail, len(errs)),
+		},
+	}
+
+	for i, e := range errs {
+		d := ODataErrorDetail{Message: e.Error()}
+
+		var (
+			ute UnexpectedTokenError
+			v   Violation
+		)
+
+		switch {
+		case errors.As(e, &ute):
+			d.Code = ute.Code
+			// the target is the field in error, only known for an unknown field.
+			if ute.Code == CodeUnknownField {
+				d.Target = ute.Token.Literal
+			}
+		case errors.As(e, &v):
+			d.Code = v.Code
+			d.Target = v.Field
+		}
+
+		oe.Error.Details[i] = d
+	}
+
+	return oe
+}
+
+// WriteODataError writes the ODataError of err as an application/json response with status 400.
+func WriteODataError(w http.ResponseWriter, err error) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+
+	return json.NewEncoder(w).Encode(NewODataError(err))
+}
+
+func unwrapParseError(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	var pe ParseError
+	if errors.As(err, &pe) {
+		return pe.Errors()
+	}
+
+	var policyErr PolicyError
+	if errors.As(err, &policyErr) {
+		return policyErr.Unwrap()
+	}
+
+	return []error{err}
+}
+
+func detail(errs []error) string {
+	switch len(errs) {
+	case 0:
+		return ""
+	case 1:
+		return errs[0].Error()
+	default:
+		return fmt.Sprintf("%d errors found in the filter expression", len(errs))
+	}
+}
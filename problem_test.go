@@ -0,0 +1,138 @@
+package goqrius
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriteProblem(t *testing.T) {
+	t.Parallel()
+
+	_, err := Parse("null eq name")
+
+	rec := httptest.NewRecorder()
+	if werr := WriteProblem(rec, err, WithProblemInstance("/users?$filter=null eq name")); werr != nil {
+		t.Fatalf("err not expected; error=%v", werr)
+	}
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+
+	if ct := rec.Header().Get("Content-Type"); ct != ProblemContentType {
+		t.Fatalf("expected content type %q, got %q", ProblemContentType, ct)
+	}
+
+	expected := `{"type":"about:blank","title":"Bad Request","status":400,` +
+		`"detail":"2 errors found in the filter expression","instance":"/users?$filter=null eq name",` +
+		`"errors":[` +
+		`{"code":"left_side_must_be_identifier","message":"left side of comparison must be an identifier",` +
+		`"position":0,"token":"null"},` +
+		`{"code":"identifier_as_value","message":"identifier can not be used as value","position":8,"token":"name"}]}` +
+		"\n"
+	if got := rec.Body.String(); got != expected {
+		t.Fatalf("unexpected problem.\nexpected: %s\ngot:      %s", expected, got)
+	}
+}
+
+func TestNewProblemType(t *testing.T) {
+	t.Parallel()
+
+	_, err := Parse("name eqq 1")
+
+	p := NewProblem(err, WithProblemType("https://example.com/problems/invalid-filter"))
+	if p.Type != "https://example.com/problems/invalid-filter" {
+		t.Fatalf("unexpected problem type %q", p.Type)
+	}
+
+	if p.Detail != err.Error() {
+		t.Fatalf("expected detail %q, got %q", err.Error(), p.Detail)
+	}
+
+	if len(p.Errors) != 1 || len(p.Errors[0].Suggestions) != 1 || p.Errors[0].Suggestions[0] != "eq" {
+		t.Fatalf("expected suggestion \"eq\", got %+v", p.Errors)
+	}
+}
+
+func TestWriteODataError(t *testing.T) {
+	t.Parallel()
+
+	_, err := Parse("@ eq 1")
+
+	rec := httptest.NewRecorder()
+	if werr := WriteODataError(rec, err); werr != nil {
+		t.Fatalf("err not expected; error=%v", werr)
+	}
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+
+	var got ODataError
+	if uerr := json.Unmarshal(rec.Body.Bytes(), &got); uerr != nil {
+		t.Fatalf("err not expected; error=%v", uerr)
+	}
+
+	if got.Error.Code != CodeInvalidFilter || got.Error.Target != ODataTarget {
+		t.Fatalf("unexpected error body %+v", got.Error)
+	}
+
+	if len(got.Error.Details) != 1 || got.Error.Details[0].Code != CodeIllegalToken ||
+		got.Error.Details[0].Target != "" {
+		t.Fatalf("unexpected error details %+v", got.Error.Details)
+	}
+}
+
+func TestNewODataErrorTarget(t *testing.T) {
+	t.Parallel()
+
+	_, err := Parse("nmae eq 'John' and age gt", WithFields("name", "age"))
+
+	got := NewODataError(err)
+	if len(got.Error.Details) != 2 {
+		t.Fatalf("unexpected error details %+v", got.Error.Details)
+	}
+
+	if d := got.Error.Details[0]; d.Code != CodeUnknownField || d.Target != "nmae" {
+		t.Fatalf("expected the unknown field as target, got %+v", d)
+	}
+
+	if d := got.Error.Details[1]; d.Code != CodeMissingExpression || d.Target != "" {
+		t.Fatalf("expected no target for a syntax error, got %+v", d)
+	}
+
+	if !errors.Is(UnexpectedTokenError{Code: CodeInvalidFilter}, ErrInvalidFilter) {
+		t.Fatal("expected CodeInvalidFilter to have a sentinel error")
+	}
+}
+
+func TestProblemInputTooLong(t *testing.T) {
+	t.Parallel()
+
+	input := "name eq '" + strings.Repeat("x", 500) + "'"
+
+	_, err := Parse(input, WithMaxLength(10))
+	if !errors.Is(err, ErrInputTooLong) {
+		t.Fatalf("expected ErrInputTooLong, got %v", err)
+	}
+
+	problem, jsonErr := json.Marshal(NewProblem(err))
+	if jsonErr != nil {
+		t.Fatalf("unexpected error: %v", jsonErr)
+	}
+
+	odata, jsonErr := json.Marshal(NewODataError(err))
+	if jsonErr != nil {
+		t.Fatalf("unexpected error: %v", jsonErr)
+	}
+
+	for _, body := range [][]byte{problem, odata} {
+		if len(body) > 300 || strings.Contains(string(body), "xxx") {
+			t.Fatalf("expected the input to not be echoed back, got %s", body)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package goqrius
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiRed   = "\x1b[31m"
+	ansiBlue  = "\x1b[34m"
+)
+
+type (
+	// RenderOption configures how RenderError displays the errors.
+	RenderOption func(*renderOptions)
+
+	renderOptions struct {
+		colors bool
+	}
+)
+
+// WithColors highlights the rendered errors using ANSI escape codes.
+func WithColors() RenderOption {
+	return func(o *renderOptions) {
+		o.colors = true
+	}
+}
+
+// RenderError renders err against the original input, showing the line and column of every error
+// and underlining the offending token, e.g.:
+//
+//	error: illegal token "@"
+//	 --> 1:2
+//	  |
+//	1 | n@me eq 'John'
+//	  |  ^
+//
+// Errors that don't carry a position are rendered with their message only.
+func RenderError(input string, err error, opts ...RenderOption) string {
+	if err == nil {
+		return ""
+	}
+
+	var o renderOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	errs := []error{err}
+
+	var pe ParseError
+	if errors.As(err, &pe) {
+		errs = pe.Errors()
+	}
+
+	rendered := make([]string, len(errs))
+	for i, e := range errs {
+		rendered[i] = o.render(input, e)
+	}
+
+	return strings.Join(rendered, "\n")
+}
+
+func (o renderOptions) render(input string, err error) string {
+	var ute UnexpectedTokenError
+	if !errors.As(err, &ute) {
+		return o.paint(ansiBold+ansiRed, "error") + ": " + o.paint(ansiBold, err.Error()) + "\n"
+	}
+
+	start := min(max(ute.Token.Position, 0), len(input))
+	end := min(max(ute.Token.End, start+1), len(input)+1)
+	lineNumber, lineStart, lineEnd := lineAt(input, start)
+	// underline until the end of the line at most.
+	end = min(end, max(lineEnd, start+1))
+
+	number := strconv.Itoa(lineNumber)
+	gutter := strings.Repeat(" ", len(number))
+	line := input[lineStart:lineEnd]
+
+	var sb strings.Builder
+
+	sb.WriteString(o.paint(ansiBold+ansiRed, "error") + ": " + o.paint(ansiBold, ute.Message) + "\n")
+	sb.WriteString(fmt.Sprintf("%s%s %d:%d\n", gutter, o.paint(ansiBlue, "-->"), lineNumber, start-lineStart+1))
+	sb.WriteString(fmt.Sprintf("%s %s\n", gutter, o.paint(ansiBlue, "|")))
+	sb.WriteString(fmt.Sprintf("%s %s %s\n", o.paint(ansiBlue, number), o.paint(ansiBlue, "|"), line))
+	sb.WriteString(fmt.Sprintf("%s %s %s%s\n", gutter, o.paint(ansiBlue, "|"),
+		padding(line[:start-lineStart]), o.paint(ansiRed, strings.Repeat("^", end-start))))
+
+	if len(ute.Suggestions) > 0 {
+		quoted := make([]string, len(ute.Suggestions))
+		for i, s := range ute.Suggestions {
+			quoted[i] = strconv.Quote(s)
+		}
+
+		sb.WriteString(fmt.Sprintf("%s %s %s: did you mean %s?\n", gutter, o.paint(ansiBlue, "="),
+			o.paint(ansiBold, "help"), strings.Join(quoted, " or ")))
+	}
+
+	return sb.String()
+}
+
+func (o renderOptions) paint(code, s string) string {
+	if !o.colors {
+		return s
+	}
+
+	return code + s + ansiReset
+}
+
+// lineAt returns the line number (starting at 1) containing the position, and the offsets of that line.
+func lineAt(input string, position int) (int, int, int) {
+	lineNumber := 1 + strings.Count(input[:position], "\n")
+	lineStart := strings.LastIndexByte(input[:position], '\n') + 1
+
+	lineEnd := strings.IndexByte(input[position:], '\n')
+	if lineEnd == -1 {
+		lineEnd = len(input)
+	} else {
+		lineEnd += position
+	}
+
+	return lineNumber, lineStart, lineEnd
+}
+
+// padding returns the whitespaces needed to align the caret below s, keeping the tabs.
+func padding(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return r
+		}
+
+		return ' '
+	}, s)
+}
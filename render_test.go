@@ -0,0 +1,114 @@
+package goqrius
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRenderError(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input    string
+		expected string
+	}{
+		"illegal character": {
+			input: "n@me eq 'John'",
+			expected: `error: illegal token "@"
+ --> 1:2
+  |
+1 | n@me eq 'John'
+  |  ^
+`,
+		},
+		"whole token is underlined": {
+			input: "name eq value",
+			expected: `error: identifier can not be used as value
+ --> 1:9
+  |
+1 | name eq value
+  |         ^^^^^
+`,
+		},
+		"missing closing paren": {
+			input: "(name eq 'John'",
+			expected: `error: expected next token to be ")", got ""
+ --> 1:16
+  |
+1 | (name eq 'John'
+  |                ^
+`,
+		},
+		"suggestions": {
+			input: "name eqq 'John'",
+			expected: `error: expected next token to be an operator, got "eqq"
+ --> 1:6
+  |
+1 | name eqq 'John'
+  |      ^^^
+  = help: did you mean "eq"?
+`,
+		},
+		"multi-line filter": {
+			input: "name eq 'John'\n\tand 1 gt 2",
+			expected: "error: left side of comparison must be an identifier\n" +
+				" --> 2:6\n" +
+				"  |\n" +
+				"2 | \tand 1 gt 2\n" +
+				"  | \t    ^\n",
+		},
+		"multiple errors": {
+			input: "null eq name",
+			expected: `error: left side of comparison must be an identifier
+ --> 1:1
+  |
+1 | null eq name
+  | ^^^^
+
+error: identifier can not be used as value
+ --> 1:9
+  |
+1 | null eq name
+  |         ^^^^
+`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(tt.input)
+			if err == nil {
+				t.Fatalf("expected error, got none")
+			}
+
+			if got := RenderError(tt.input, err); got != tt.expected {
+				t.Fatalf("unexpected rendered error.\nexpected:\n%s\ngot:\n%s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRenderErrorWithColors(t *testing.T) {
+	t.Parallel()
+
+	input := "n@me eq 'John'"
+
+	_, err := Parse(input)
+
+	got := RenderError(input, err, WithColors())
+	if !strings.Contains(got, ansiRed+"^"+ansiReset) {
+		t.Fatalf("expected colored caret, got %q", got)
+	}
+}
+
+func TestRenderErrorWithoutPosition(t *testing.T) {
+	t.Parallel()
+
+	got := RenderError("name eq 1", errors.New("boom"))
+	if got != "error: boom\n" {
+		t.Fatalf("unexpected rendered error %q", got)
+	}
+}
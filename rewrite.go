@@ -0,0 +1,283 @@
+package goqrius
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidRewrite is returned when a rewrite breaks the structure of the tree,
+// e.g. replacing the left side of a FilterExpr with something else than an *Identifier.
+var ErrInvalidRewrite = errors.New("invalid rewrite")
+
+// Cursor describes a node encountered during Apply, and allows to modify the tree at that node.
+type Cursor struct {
+	node   Expression
+	parent Expression
+	name   string
+	err    error
+	// inserts wrap the node once it's traversed, so the inserted expressions are not traversed.
+	inserts []func(Expression) Expression
+}
+
+// Node returns the current node, or nil if it was deleted.
+func (c *Cursor) Node() Expression { return c.node }
+
+// Parent returns the parent of the current node, or nil for the root.
+func (c *Cursor) Parent() Expression { return c.parent }
+
+// Name returns the name of the parent field that contains the current node, "Left" or "Right",
+// or an empty string for the root.
+func (c *Cursor) Name() string { return c.name }
+
+// Replace replaces the current node with expr. Replacing with nil is the same as Delete.
+//
+// The left side of a FilterExpr can only be replaced by an *Identifier, and the right side by a Value.
+// Any other node, i.e. the root and the operands of and, or and not, can't be replaced by an *Identifier or
+// a literal, as they can only be used in a comparison. A nil pointer, e.g. (*FilterExpr)(nil), is never valid.
+func (c *Cursor) Replace(expr Expression) {
+	if expr == nil {
+		c.Delete()
+
+		return
+	}
+
+	if isNilNode(expr) {
+		c.err = fmt.Errorf("%w: a node can not be replaced by a nil %T", ErrInvalidRewrite, expr)
+
+		return
+	}
+
+	_, isFilter := c.parent.(*FilterExpr)
+
+	switch {
+	case isFilter && c.name == "Left":
+		if _, ok := expr.(*Identifier); !ok {
+			c.err = fmt.Errorf("%w: left side of a comparison can not be replaced by %T", ErrInvalidRewrite, expr)
+
+			return
+		}
+	case isFilter && c.name == "Right":
+		if _, ok := expr.(Value); !ok {
+			c.err = fmt.Errorf("%w: right side of a comparison can not be replaced by %T", ErrInvalidRewrite, expr)
+
+			return
+		}
+	case isLeaf(expr):
+		c.err = fmt.Errorf("%w: %T can only be used in a comparison", ErrInvalidRewrite, expr)
+
+		return
+	}
+
+	c.node = expr
+}
+
+// Delete removes the current node from the tree.
+// Deleting an operand of an and/or expression replaces that expression with the other operand,
+// and deleting the operand of a not expression or a side of a comparison deletes the whole expression.
+func (c *Cursor) Delete() {
+	c.node = nil
+}
+
+// InsertAnd replaces the current node with `node and expr`, once the node is traversed.
+// As astutil.Apply does, expr is not traversed, so it can be inserted from pre without visiting it again.
+// expr can't be nil, an *Identifier or a literal, see Replace.
+func (c *Cursor) InsertAnd(expr Expression) {
+	c.insert(expr, func(node Expression) Expression { return &AndExpr{Left: node, Right: expr} })
+}
+
+// InsertOr replaces the current node with `node or expr`, once the node is traversed, see InsertAnd.
+func (c *Cursor) InsertOr(expr Expression) {
+	c.insert(expr, func(node Expression) Expression { return &OrExpr{Left: node, Right: expr} })
+}
+
+func (c *Cursor) insert(expr Expression, f func(Expression) Expression) {
+	switch _, isFilter := c.parent.(*FilterExpr); {
+	case isFilter:
+		c.err = fmt.Errorf("%w: can not insert an expression in a comparison", ErrInvalidRewrite)
+	case c.node == nil:
+		c.err = fmt.Errorf("%w: can not insert an expression next to a deleted node", ErrInvalidRewrite)
+	case isNilNode(expr):
+		c.err = fmt.Errorf("%w: can not insert a nil expression", ErrInvalidRewrite)
+	case isLeaf(expr):
+		c.err = fmt.Errorf("%w: %T can only be used in a comparison", ErrInvalidRewrite, expr)
+	default:
+		c.inserts = append(c.inserts, func(node Expression) Expression {
+			// deleting the node leaves the inserted expression alone, as deleting an operand does.
+			if node == nil {
+				return expr
+			}
+
+			return f(node)
+		})
+	}
+}
+
+// result returns the node with the inserted expressions.
+func (c *Cursor) result() Expression {
+	node := c.node
+	for _, insert := range c.inserts {
+		node = insert(node)
+	}
+
+	return node
+}
+
+// Apply traverses expr in depth-first order, and returns the tree modified through the Cursor.
+//
+// pre is called for every node before its children, if it returns false, the children and post are skipped.
+// post is called for every node after its children, if it returns false, the traversal is stopped.
+// Either pre or post can be nil.
+//
+// The input tree is not modified, the nodes in the path of a modification are copied.
+// If the whole tree is deleted, nil is returned.
+func Apply(expr Expression, pre, post func(*Cursor) bool) (Expression, error) {
+	a := applier{pre: pre, post: post}
+
+	result := a.apply(nil, "", expr)
+	if a.err != nil {
+		return nil, a.err
+	}
+
+	return result, nil
+}
+
+// Rewrite transforms expr bottom-up, replacing every node with the result of fn.
+// Returning the same node leaves it untouched, and returning nil deletes it, see Cursor.Delete.
+func Rewrite(expr Expression, fn func(Expression) (Expression, error)) (Expression, error) {
+	var fnErr error
+
+	result, err := Apply(expr, nil, func(c *Cursor) bool {
+		replacement, err := fn(c.Node())
+		if err != nil {
+			fnErr = err
+
+			return false
+		}
+
+		if replacement != c.Node() {
+			c.Replace(replacement)
+		}
+
+		return true
+	})
+	if fnErr != nil {
+		return nil, fnErr
+	}
+
+	return result, err
+}
+
+type applier struct {
+	pre, post func(*Cursor) bool
+	err       error
+	stopped   bool
+}
+
+func (a *applier) apply(parent Expression, name string, node Expression) Expression {
+	if node == nil || a.stopped {
+		return node
+	}
+
+	c := &Cursor{node: node, parent: parent, name: name}
+
+	if a.pre != nil && !a.pre(c) {
+		a.check(c)
+
+		return c.result()
+	}
+
+	if !a.check(c) || c.node == nil {
+		return c.result()
+	}
+
+	c.node = a.applyChildren(c.node)
+
+	if a.post != nil && !a.post(c) {
+		a.stopped = true
+	}
+
+	a.check(c)
+
+	return c.result()
+}
+
+// check records the error of the cursor, stopping the traversal.
+func (a *applier) check(c *Cursor) bool {
+	if c.err != nil && a.err == nil {
+		a.err = c.err
+		a.stopped = true
+	}
+
+	return a.err == nil
+}
+
+// applyChildren applies the traversal to the children of node, returning a copy of node if any of them changed.
+func (a *applier) applyChildren(node Expression) Expression {
+	switch n := node.(type) {
+	case *AndExpr:
+		left, right := a.apply(n, "Left", n.Left), a.apply(n, "Right", n.Right)
+		if left == n.Left && right == n.Right {
+			return n
+		}
+
+		switch {
+		case left == nil:
+			return right
+		case right == nil:
+			return left
+		default:
+			return &AndExpr{Left: left, Right: right, Span: n.Span}
+		}
+	case *OrExpr:
+		left, right := a.apply(n, "Left", n.Left), a.apply(n, "Right", n.Right)
+		if left == n.Left && right == n.Right {
+			return n
+		}
+
+		switch {
+		case left == nil:
+			return right
+		case right == nil:
+			return left
+		default:
+			return &OrExpr{Left: left, Right: right, Span: n.Span}
+		}
+	case *NotExpr:
+		right := a.apply(n, "Right", n.Right)
+		if right == n.Right {
+			return n
+		}
+
+		if right == nil {
+			return nil
+		}
+
+		return &NotExpr{Right: right, Span: n.Span}
+	case *FilterExpr:
+		return a.applyFilter(n)
+	default:
+		return node
+	}
+}
+
+func (a *applier) applyFilter(n *FilterExpr) Expression {
+	var left Expression
+	if n.Left != nil {
+		left = a.apply(n, "Left", n.Left)
+	}
+
+	right := a.apply(n, "Right", n.Right)
+
+	if left == nil || right == nil {
+		return nil
+	}
+
+	ident, _ := left.(*Identifier)
+	value, _ := right.(Value)
+
+	if ident == n.Left && value == n.Right {
+		return n
+	}
+
+	return &FilterExpr{Left: ident, Operator: n.Operator, Right: value, Span: n.Span}
+}
@@ -0,0 +1,309 @@
+package goqrius
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRewrite(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input          string
+		fn             func(Expression) (Expression, error)
+		expectedString string
+	}{
+		"rename field": {
+			input: "name eq 'John' and age gt 18",
+			fn: func(e Expression) (Expression, error) {
+				if i, ok := e.(*Identifier); ok && i.Value == "name" {
+					return &Identifier{Value: "first_name"}, nil
+				}
+
+				return e, nil
+			},
+			expectedString: "((first_name eq 'John') and (age gt 18))",
+		},
+		"drop forbidden clause": {
+			input: "name eq 'John' and (password eq 'secret' or age gt 18)",
+			fn: func(e Expression) (Expression, error) {
+				if f, ok := e.(*FilterExpr); ok && f.Left.Value == "password" {
+					return nil, nil
+				}
+
+				return e, nil
+			},
+			expectedString: "((name eq 'John') and (age gt 18))",
+		},
+		"drop not operand deletes the not": {
+			input: "name eq 'John' and not password eq 'secret'",
+			fn: func(e Expression) (Expression, error) {
+				if i, ok := e.(*Identifier); ok && i.Value == "password" {
+					return nil, nil
+				}
+
+				return e, nil
+			},
+			expectedString: "(name eq 'John')",
+		},
+		"replace value": {
+			input: "age gt 18",
+			fn: func(e Expression) (Expression, error) {
+				if _, ok := e.(*IntegerLiteral); ok {
+					return &IntegerLiteral{Value: "21"}, nil
+				}
+
+				return e, nil
+			},
+			expectedString: "(age gt 21)",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr := MustParse(tt.input)
+			original := expr.String()
+
+			got, err := Rewrite(expr, tt.fn)
+			if err != nil {
+				t.Fatalf("err not expected; error=%v", err)
+			}
+
+			if got.String() != tt.expectedString {
+				t.Fatalf("unexpected AST string. expected=%q got=%q", tt.expectedString, got.String())
+			}
+
+			if expr.String() != original {
+				t.Fatalf("input tree was modified. expected=%q got=%q", original, expr.String())
+			}
+		})
+	}
+}
+
+func TestRewriteDeleteAll(t *testing.T) {
+	t.Parallel()
+
+	got, err := Rewrite(MustParse("name eq 'John'"), func(Expression) (Expression, error) { return nil, nil })
+	if err != nil {
+		t.Fatalf("err not expected; error=%v", err)
+	}
+
+	if got != nil {
+		t.Fatalf("expected nil expression, got %v", got)
+	}
+}
+
+func TestRewriteErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		fn          func(Expression) (Expression, error)
+		expectedErr error
+	}{
+		"left side must stay an identifier": {
+			fn: func(e Expression) (Expression, error) {
+				if _, ok := e.(*Identifier); ok {
+					return &StringLiteral{Value: "name"}, nil
+				}
+
+				return e, nil
+			},
+			expectedErr: ErrInvalidRewrite,
+		},
+		"right side must stay a value": {
+			fn: func(e Expression) (Expression, error) {
+				if _, ok := e.(*StringLiteral); ok {
+					return &Identifier{Value: "name"}, nil
+				}
+
+				return e, nil
+			},
+			expectedErr: ErrInvalidRewrite,
+		},
+		"error from the function": {
+			fn: func(e Expression) (Expression, error) {
+				if _, ok := e.(*FilterExpr); ok {
+					return nil, ErrUnknownField
+				}
+
+				return e, nil
+			},
+			expectedErr: ErrUnknownField,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Rewrite(MustParse("name eq 'John'"), tt.fn)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestApplyReplaceErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		filter      string
+		replacement Expression
+	}{
+		"operand of and with a value":    {filter: "a eq 1 and b eq 2", replacement: &IntegerLiteral{Value: "1"}},
+		"operand of or with an ident":    {filter: "a eq 1 or b eq 2", replacement: &Identifier{Value: "a"}},
+		"operand of not with a null":     {filter: "not a eq 1", replacement: &Null{}},
+		"operand of and with a nil node": {filter: "a eq 1 and b eq 2", replacement: (*FilterExpr)(nil)},
+		"comparison with a nil value":    {filter: "a eq 1", replacement: (*StringLiteral)(nil)},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Apply(MustParse(tt.filter), func(c *Cursor) bool {
+				if c.Parent() != nil {
+					c.Replace(tt.replacement)
+				}
+
+				return true
+			}, nil)
+			if !errors.Is(err, ErrInvalidRewrite) {
+				t.Fatalf("expected error %v, got %v", ErrInvalidRewrite, err)
+			}
+		})
+	}
+
+	root, err := Apply(MustParse("a eq 1"), func(c *Cursor) bool {
+		c.Replace(&BooleanLiteral{Value: true})
+
+		return false
+	}, nil)
+	if !errors.Is(err, ErrInvalidRewrite) {
+		t.Fatalf("expected the root to not be replaced by a literal, got %v, %v", root, err)
+	}
+}
+
+func TestApplyInsertTenant(t *testing.T) {
+	t.Parallel()
+
+	tenant := &FilterExpr{Left: &Identifier{Value: "tenant_id"}, Operator: Eq, Right: &IntegerLiteral{Value: "7"}}
+
+	got, err := Apply(MustParse("name eq 'John' or age gt 18"), func(c *Cursor) bool {
+		if c.Parent() == nil {
+			c.InsertAnd(tenant)
+		}
+
+		return false
+	}, nil)
+	if err != nil {
+		t.Fatalf("err not expected; error=%v", err)
+	}
+
+	expected := "(((name eq 'John') or (age gt 18)) and (tenant_id eq 7))"
+	if got.String() != expected {
+		t.Fatalf("unexpected AST string. expected=%q got=%q", expected, got.String())
+	}
+}
+
+func TestApplyInsertFromPre(t *testing.T) {
+	t.Parallel()
+
+	tenant := &FilterExpr{Left: &Identifier{Value: "tenant"}, Operator: Eq, Right: &IntegerLiteral{Value: "1"}}
+
+	var visited []string
+
+	got, err := Apply(MustParse("a eq 1 or b eq 2"), func(c *Cursor) bool {
+		if f, ok := c.Node().(*FilterExpr); ok {
+			visited = append(visited, f.Left.Value)
+
+			if f.Left.Value == "a" {
+				c.InsertAnd(tenant)
+			}
+		}
+
+		return true
+	}, func(c *Cursor) bool {
+		if f, ok := c.Node().(*FilterExpr); ok && f.Left.Value == "b" {
+			c.InsertOr(tenant)
+		}
+
+		return true
+	})
+	if err != nil {
+		t.Fatalf("err not expected; error=%v", err)
+	}
+
+	expected := "(((a eq 1) and (tenant eq 1)) or ((b eq 2) or (tenant eq 1)))"
+	if got.String() != expected {
+		t.Fatalf("unexpected AST string. expected=%q got=%q", expected, got.String())
+	}
+
+	if !slices.Equal(visited, []string{"a", "b"}) {
+		t.Fatalf("expected the inserted expressions to not be visited, got %v", visited)
+	}
+}
+
+func TestApplyInsertErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]Expression{
+		"nil":          nil,
+		"nil node":     (*FilterExpr)(nil),
+		"identifier":   &Identifier{Value: "a"},
+		"literal":      &IntegerLiteral{Value: "1"},
+		"null literal": &Null{},
+	}
+
+	for name, expr := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Apply(MustParse("a eq 1 or b eq 2"), func(c *Cursor) bool {
+				if c.Parent() == nil {
+					c.InsertAnd(expr)
+				}
+
+				return true
+			}, nil)
+			if !errors.Is(err, ErrInvalidRewrite) {
+				t.Fatalf("expected error %v, got %v", ErrInvalidRewrite, err)
+			}
+		})
+	}
+}
+
+func TestApplyCursor(t *testing.T) {
+	t.Parallel()
+
+	var names []string
+
+	_, err := Apply(MustParse("not name eq 'John'"), func(c *Cursor) bool {
+		names = append(names, c.Name())
+
+		return true
+	}, nil)
+	if err != nil {
+		t.Fatalf("err not expected; error=%v", err)
+	}
+
+	expected := []string{"", "Right", "Left", "Right"}
+	if !slices.Equal(names, expected) {
+		t.Fatalf("unexpected cursor names. expected=%v got=%v", expected, names)
+	}
+
+	_, err = Apply(MustParse("name eq 'John'"), func(c *Cursor) bool {
+		if _, ok := c.Node().(*Identifier); ok {
+			c.InsertAnd(&Identifier{Value: "age"})
+		}
+
+		return true
+	}, nil)
+	if !errors.Is(err, ErrInvalidRewrite) {
+		t.Fatalf("expected error %v, got %v", ErrInvalidRewrite, err)
+	}
+}
@@ -0,0 +1,260 @@
+package goqrius
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/golaxo/goqrius/internal/token"
+)
+
+// FieldType is the type of a field declared in a Schema.
+type FieldType string
+
+const (
+	// TypeString fields are compared with string literals, by their bytes.
+	TypeString FieldType = "string"
+	// TypeInt fields are compared with integer literals.
+	TypeInt FieldType = "int"
+	// TypeDecimal fields are compared with integer literals, the only numbers of the language.
+	TypeDecimal FieldType = "decimal"
+	// TypeBool fields are compared with true and false, only with eq and ne.
+	TypeBool FieldType = "bool"
+	// TypeDateTime fields are compared with RFC 3339 dates, e.g. '2025-01-02T15:04:05Z'.
+	TypeDateTime FieldType = "datetime"
+	// TypeGUID fields are compared with GUIDs, e.g. '01234567-89ab-cdef-0123-456789abcdef', only with eq and ne.
+	TypeGUID FieldType = "guid"
+	// TypeEnum fields are compared with one of the SchemaField.Values, only with eq and ne.
+	TypeEnum FieldType = "enum"
+	// TypeCollection fields can only be compared with null.
+	TypeCollection FieldType = "collection"
+	// TypeObject fields can only be compared with null, and their SchemaField.Fields are read with dotted identifiers.
+	TypeObject FieldType = "object"
+)
+
+type (
+	// Schema declares, by name, the fields that can be used in a filter expression, see Check.
+	Schema map[string]SchemaField
+
+	// SchemaField declares the type of a field.
+	SchemaField struct {
+		Type FieldType
+		// Values are the allowed values of a TypeEnum field.
+		Values []string
+		// Fields are the nested fields of a TypeObject field, e.g. city in `address.city eq 'Madrid'`.
+		Fields Schema
+		// Dynamic marks a TypeObject whose nested fields are only known at runtime, e.g. a map,
+		// so any nested field is accepted, without checking its type or operator.
+		Dynamic bool
+		// Operators restricts the operators allowed, the ones of the Type if empty, see FieldType.Operators.
+		Operators []FilterOperator
+	}
+)
+
+// Operators returns the operators that can be applied to a field of type t.
+func (t FieldType) Operators() []FilterOperator {
+	switch t {
+	case TypeString, TypeInt, TypeDecimal, TypeDateTime:
+		return []FilterOperator{Eq, NotEq, GreaterThan, GreaterThanOrEqual, LessThan, LessThanOrEqual}
+	case TypeBool, TypeGUID, TypeEnum, TypeCollection, TypeObject:
+		return []FilterOperator{Eq, NotEq}
+	default:
+		return nil
+	}
+}
+
+// Check reports whether expr is valid for schema, returning a ParseError with an UnexpectedTokenError for:
+//   - every unknown field, CodeUnknownField, suggesting the closest declared ones.
+//   - every operator that is not allowed for the field, CodeOperatorNotAllowed.
+//   - every value that doesn't match the type of the field, CodeTypeMismatch, e.g. `age eq 'abc'` with an int age.
+//
+// The comparisons with null are valid for any field, and the BadExpr and MissingExpr of a partial tree are skipped.
+func Check(expr Expression, schema Schema) error {
+	errs := check(expr, schema)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	parseErrors := make([]error, len(errs))
+	for i, err := range errs {
+		parseErrors[i] = err
+	}
+
+	return ParseError{errors: parseErrors}
+}
+
+func check(expr Expression, schema Schema) []UnexpectedTokenError {
+	var errs []UnexpectedTokenError
+
+	Inspect(expr, func(e Expression) bool {
+		if f, ok := e.(*FilterExpr); ok && f.Left != nil {
+			errs = append(errs, schema.checkFilter(f)...)
+		}
+
+		return true
+	})
+
+	return errs
+}
+
+// checkFilter returns the errors of the comparison f.
+// The errors of the operator are reported at the position of the comparison, as the operator has no node.
+func (s Schema) checkFilter(f *FilterExpr) []UnexpectedTokenError {
+	field, dynamic, ok := s.lookup(f.Left.Value)
+	switch {
+	case dynamic:
+		return nil
+	case !ok:
+		return []UnexpectedTokenError{{
+			Token:       token.Token{Type: token.Ident, Literal: f.Left.Value, Position: f.Left.Pos(), End: f.Left.End()},
+			Code:        CodeUnknownField,
+			Message:     fmt.Sprintf("unknown field %q", f.Left.Value),
+			Suggestions: suggest(f.Left.Value, s.paths("")),
+		}}
+	}
+
+	var errs []UnexpectedTokenError
+
+	if !field.allows(f.Operator) {
+		errs = append(errs, UnexpectedTokenError{
+			Token: token.Token{
+				Type: token.Type(f.Operator), Literal: string(f.Operator), Position: f.Pos(), End: f.End(),
+			},
+			Code:    CodeOperatorNotAllowed,
+			Message: fmt.Sprintf("operator %s is not allowed for the %s field %q", f.Operator, field.Type, f.Left.Value),
+		})
+	}
+
+	switch lit := f.Right.(type) {
+	case nil, *Null, *BadExpr, *MissingExpr:
+	default:
+		if err, mismatch := field.checkValue(f.Left.Value, lit); mismatch {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// lookup returns the field of the dotted path,
+// and whether it's nested in a Dynamic TypeObject, so its type is unknown.
+func (s Schema) lookup(path string) (SchemaField, bool, bool) {
+	var field SchemaField
+
+	fields := s
+
+	for segment := range strings.SplitSeq(path, ".") {
+		if field.Type == TypeObject && field.Dynamic {
+			return SchemaField{}, true, true
+		}
+
+		var ok bool
+		if field, ok = fields[segment]; !ok {
+			return SchemaField{}, false, false
+		}
+
+		fields = field.Fields
+	}
+
+	return field, false, true
+}
+
+// paths returns the dotted paths of all the fields, sorted, prefixed with prefix.
+func (s Schema) paths(prefix string) []string {
+	paths := make([]string, 0, len(s))
+	for name, field := range s {
+		paths = append(paths, prefix+name)
+		paths = append(paths, field.Fields.paths(prefix+name+".")...)
+	}
+
+	slices.Sort(paths)
+
+	return paths
+}
+
+func (f SchemaField) allows(operator FilterOperator) bool {
+	if !slices.Contains(f.Type.Operators(), operator) {
+		return false
+	}
+
+	return len(f.Operators) == 0 || slices.Contains(f.Operators, operator)
+}
+
+// checkValue returns the error of comparing the field called name with lit, and whether they don't match.
+func (f SchemaField) checkValue(name string, lit Value) (UnexpectedTokenError, bool) {
+	err := UnexpectedTokenError{
+		Token:   valueToken(lit),
+		Code:    CodeTypeMismatch,
+		Message: fmt.Sprintf("can not compare the %s field %q with %s", f.Type, name, lit),
+	}
+
+	switch v := lit.(type) {
+	case *IntegerLiteral:
+		return err, f.Type != TypeInt && f.Type != TypeDecimal
+	case *BooleanLiteral:
+		return err, f.Type != TypeBool
+	case *StringLiteral:
+		switch f.Type {
+		case TypeString:
+			return err, false
+		case TypeDateTime:
+			_, parseErr := v.Time()
+
+			return err, parseErr != nil
+		case TypeGUID:
+			return err, !isGUID(v.Value)
+		case TypeEnum:
+			err.Suggestions = suggest(v.Value, f.Values)
+
+			return err, !slices.Contains(f.Values, v.Value)
+		}
+	}
+
+	return err, true
+}
+
+// valueToken returns the token the lexer reads for lit.
+func valueToken(lit Value) token.Token {
+	tok := token.Token{Literal: lit.String(), Position: lit.Pos(), End: lit.End()}
+
+	switch v := lit.(type) {
+	case *IntegerLiteral:
+		tok.Type = token.Int
+	case *StringLiteral:
+		tok.Type = token.String
+		tok.Literal = v.Value
+	case *BooleanLiteral:
+		tok.Type = token.False
+		if v.Value {
+			tok.Type = token.True
+		}
+	case *Null:
+		tok.Type = token.Null
+	}
+
+	return tok
+}
+
+// isGUID reports whether s is a GUID with the format xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx, in hexadecimal digits.
+func isGUID(s string) bool {
+	const length = 36
+
+	if len(s) != length {
+		return false
+	}
+
+	for i, c := range []byte(s) {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+				return false
+			}
+		}
+	}
+
+	return true
+}
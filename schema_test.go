@@ -0,0 +1,191 @@
+package goqrius
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func testSchema() Schema {
+	return Schema{
+		"name":    {Type: TypeString},
+		"age":     {Type: TypeInt},
+		"price":   {Type: TypeDecimal},
+		"active":  {Type: TypeBool},
+		"created": {Type: TypeDateTime},
+		"id":      {Type: TypeGUID},
+		"status":  {Type: TypeEnum, Values: []string{"active", "inactive"}},
+		"tags":    {Type: TypeCollection},
+		"email":   {Type: TypeString, Operators: []FilterOperator{Eq, NotEq}},
+		"address": {Type: TypeObject, Fields: Schema{
+			"city": {Type: TypeString},
+			"zip":  {Type: TypeInt},
+		}},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		filter   string
+		expected []ErrorCode
+	}{
+		"valid string":            {filter: "name eq 'John'"},
+		"valid int":               {filter: "age gt 18"},
+		"valid decimal":           {filter: "price le 10"},
+		"valid bool":              {filter: "active eq true"},
+		"valid datetime":          {filter: "created ge '2025-01-02T15:04:05Z'"},
+		"valid guid":              {filter: "id eq '01234567-89ab-cdef-0123-456789ABCDEF'"},
+		"valid enum":              {filter: "status ne 'inactive'"},
+		"valid nested":            {filter: "address.city eq 'Madrid' and address.zip lt 28100"},
+		"null with any type":      {filter: "tags eq null or address ne null or age eq null"},
+		"unknown field":           {filter: "nmae eq 'John'", expected: []ErrorCode{CodeUnknownField}},
+		"unknown nested field":    {filter: "address.country eq 'Spain'", expected: []ErrorCode{CodeUnknownField}},
+		"path through a scalar":   {filter: "name.first eq 'J'", expected: []ErrorCode{CodeUnknownField}},
+		"string with int":         {filter: "age eq 'abc'", expected: []ErrorCode{CodeTypeMismatch}},
+		"int with string":         {filter: "name eq 1", expected: []ErrorCode{CodeTypeMismatch}},
+		"bool with int":           {filter: "active eq 1", expected: []ErrorCode{CodeTypeMismatch}},
+		"invalid datetime":        {filter: "created gt 'yesterday'", expected: []ErrorCode{CodeTypeMismatch}},
+		"invalid guid":            {filter: "id eq '0123'", expected: []ErrorCode{CodeTypeMismatch}},
+		"invalid enum value":      {filter: "status eq 'actve'", expected: []ErrorCode{CodeTypeMismatch}},
+		"collection with value":   {filter: "tags eq 'go'", expected: []ErrorCode{CodeTypeMismatch}},
+		"ordered bool":            {filter: "active gt false", expected: []ErrorCode{CodeOperatorNotAllowed}},
+		"ordered enum":            {filter: "status lt 'active'", expected: []ErrorCode{CodeOperatorNotAllowed}},
+		"restricted operators":    {filter: "email gt 'a'", expected: []ErrorCode{CodeOperatorNotAllowed}},
+		"operator and type":       {filter: "active gt 1", expected: []ErrorCode{CodeOperatorNotAllowed, CodeTypeMismatch}},
+		"every error of the tree": {filter: "not (nmae eq 'John' or age eq 'x')", expected: []ErrorCode{CodeUnknownField, CodeTypeMismatch}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Check(MustParse(tt.filter), testSchema())
+			if len(tt.expected) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			var pe ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("expected a ParseError, got %v", err)
+			}
+
+			codes := make([]ErrorCode, len(pe.Errors()))
+			for i, e := range pe.Errors() {
+				var ute UnexpectedTokenError
+				if !errors.As(e, &ute) {
+					t.Fatalf("expected an UnexpectedTokenError, got %v", e)
+				}
+
+				codes[i] = ute.Code
+			}
+
+			if !slices.Equal(codes, tt.expected) {
+				t.Fatalf("expected codes %v, got %v", tt.expected, codes)
+			}
+		})
+	}
+}
+
+func TestCheckErrorDetails(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		filter              string
+		expectedPosition    int
+		expectedToken       string
+		expectedSuggestions []string
+		expectedErr         error
+	}{
+		"unknown field": {
+			filter: "age gt 1 and nmae eq 'John'", expectedPosition: 13, expectedToken: "nmae",
+			expectedSuggestions: []string{"name"}, expectedErr: ErrUnknownField,
+		},
+		"unknown nested field": {
+			filter: "address.ctiy eq 'Madrid'", expectedPosition: 0, expectedToken: "address.ctiy",
+			expectedSuggestions: []string{"address.city", "address.zip"}, expectedErr: ErrUnknownField,
+		},
+		"type mismatch": {
+			filter: "age eq 'abc'", expectedPosition: 7, expectedToken: "abc", expectedErr: ErrTypeMismatch,
+		},
+		"enum value": {
+			filter: "status eq 'actve'", expectedPosition: 10, expectedToken: "actve",
+			expectedSuggestions: []string{"active"}, expectedErr: ErrTypeMismatch,
+		},
+		"operator not allowed": {
+			filter: "name eq 'John' and active lt true", expectedPosition: 19, expectedToken: "lt",
+			expectedErr: ErrOperatorNotAllowed,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Check(MustParse(tt.filter), testSchema())
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected %v, got %v", tt.expectedErr, err)
+			}
+
+			var ute UnexpectedTokenError
+			if !errors.As(err, &ute) {
+				t.Fatalf("expected an UnexpectedTokenError, got %v", err)
+			}
+
+			if ute.Token.Position != tt.expectedPosition || ute.Token.Literal != tt.expectedToken {
+				t.Fatalf("expected %q at position %d, got %q at position %d",
+					tt.expectedToken, tt.expectedPosition, ute.Token.Literal, ute.Token.Position)
+			}
+
+			if !slices.Equal(ute.Suggestions, tt.expectedSuggestions) {
+				t.Fatalf("expected suggestions %v, got %v", tt.expectedSuggestions, ute.Suggestions)
+			}
+		})
+	}
+}
+
+func TestParseWithSchema(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		filter         string
+		opts           []ParseOption
+		expectedErrors int
+	}{
+		"valid":               {filter: "name eq 'John' and age gt 18"},
+		"schema errors":       {filter: "nmae eq 'John' and age eq 'x'", expectedErrors: 2},
+		"with syntax errors":  {filter: "age eq 'x' and name eq", expectedErrors: 2},
+		"limited errors":      {filter: "a eq 1 and b eq 2 and c eq 3", opts: []ParseOption{WithMaxErrors(2)}, expectedErrors: 3},
+		"empty filter":        {filter: ""},
+		"null is always fine": {filter: "tags eq null"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(tt.filter, append(tt.opts, WithSchema(testSchema()))...)
+			if tt.expectedErrors == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			var pe ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("expected a ParseError, got %v", err)
+			}
+
+			if len(pe.Errors()) != tt.expectedErrors {
+				t.Fatalf("expected %d errors, got %d: %v", tt.expectedErrors, len(pe.Errors()), err)
+			}
+		})
+	}
+}
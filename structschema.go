@@ -0,0 +1,202 @@
+package goqrius
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidSchema is returned when a Schema can not be derived from a Go type, see SchemaFromStruct.
+var ErrInvalidSchema = errors.New("invalid schema")
+
+// tagOptions are the options of the goqrius tag after the name, e.g. `goqrius:"name,ops=eq|ne,alias=fullName"`.
+type tagOptions struct {
+	operators []FilterOperator
+	aliases   []string
+	fieldType FieldType
+	values    []string
+}
+
+// SchemaFromStruct derives the Schema of the struct T, or pointer to struct, with the fields Match resolves:
+// the exported fields, named by their goqrius tag, their json tag or their Go name, and the promoted fields of
+// the embedded structs. The fields tagged with "-" are excluded.
+//
+// The type of a field is inferred from its Go type: strings are TypeString, integers and *big.Int are TypeInt,
+// floats and json.Number are TypeDecimal, time.Time is TypeDateTime, slices and arrays are TypeCollection,
+// and structs and maps are TypeObject, with the nested fields of the structs, and Dynamic for the maps,
+// so any key is accepted, e.g. `labels.role eq 'admin'`.
+// A field of a struct type that contains it, e.g. `Parent *Node` in Node, has no Fields,
+// so it can only be compared with null, and its nested fields are unknown.
+// The fields of other types, e.g. interfaces, are excluded unless they declare their type.
+//
+// The goqrius tag accepts these options after the name, separated by commas:
+//   - ops: the allowed operators, separated by |, e.g. `goqrius:"status,ops=eq|ne"`.
+//   - alias: other names of the field, separated by |, e.g. `goqrius:"name,alias=fullName|displayName"`.
+//     Match and Compile resolve them too. An alias that is the name of another field resolves to that field.
+//   - type: the FieldType, overriding the inferred one, e.g. `goqrius:"id,type=guid"`.
+//   - values: the values of a TypeEnum field, separated by |, e.g. `goqrius:"status,type=enum,values=active|inactive"`.
+//
+// ErrInvalidSchema is returned for an unknown option, type or operator, e.g. `ops=contains`,
+// and for an operator that can't be applied to the type of the field.
+func SchemaFromStruct[T any]() (Schema, error) {
+	t := reflect.TypeFor[T]()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %s is not a struct", ErrInvalidSchema, t)
+	}
+
+	return structSchema(t, map[reflect.Type]bool{t: true})
+}
+
+// structSchema returns the schema of the struct t, visiting the types in visiting only once, to avoid cycles.
+func structSchema(t reflect.Type, visiting map[reflect.Type]bool) (Schema, error) {
+	schema := make(Schema)
+	fields := structFields(t)
+
+	// sorted, to always report the same error.
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		sf := t.FieldByIndex(fields[name])
+
+		field, ok, err := schemaField(sf, visiting)
+		if err != nil {
+			return nil, fmt.Errorf("field %s of %s: %w", sf.Name, t, err)
+		}
+
+		if ok {
+			schema[name] = field
+		}
+	}
+
+	return schema, nil
+}
+
+// schemaField returns the declaration of sf, and whether it can be used in a filter expression.
+func schemaField(sf reflect.StructField, visiting map[reflect.Type]bool) (SchemaField, bool, error) {
+	opts, err := parseTagOptions(sf.Tag.Get(TagName))
+	if err != nil {
+		return SchemaField{}, false, err
+	}
+
+	t := sf.Type
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	field := SchemaField{Type: opts.fieldType, Values: opts.values, Operators: opts.operators}
+	if field.Type == "" {
+		field.Type = inferFieldType(t)
+	}
+
+	switch {
+	case field.Type == "":
+		return SchemaField{}, false, nil
+	case field.Type == TypeEnum && len(field.Values) == 0:
+		return SchemaField{}, false, fmt.Errorf("%w: enum without values", ErrInvalidSchema)
+	}
+
+	for _, operator := range field.Operators {
+		if !slices.Contains(field.Type.Operators(), operator) {
+			return SchemaField{}, false, fmt.Errorf("%w: operator %s can not be applied to a %s", ErrInvalidSchema, operator, field.Type)
+		}
+	}
+
+	if field.Type == TypeObject && t.Kind() == reflect.Map {
+		field.Dynamic = true
+	}
+
+	if field.Type == TypeObject && t.Kind() == reflect.Struct && !visiting[t] {
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		if field.Fields, err = structSchema(t, visiting); err != nil {
+			return SchemaField{}, false, err
+		}
+	}
+
+	return field, true, nil
+}
+
+// inferFieldType returns the FieldType of the Go type t, or "" if it has none.
+//
+//nolint:exhaustive // the other kinds have no type.
+func inferFieldType(t reflect.Type) FieldType {
+	switch t {
+	case timeType:
+		return TypeDateTime
+	case bigIntType:
+		return TypeInt
+	case jsonNumberType:
+		return TypeDecimal
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return TypeString
+	case reflect.Bool:
+		return TypeBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return TypeInt
+	case reflect.Float32, reflect.Float64:
+		return TypeDecimal
+	case reflect.Slice, reflect.Array:
+		return TypeCollection
+	case reflect.Struct, reflect.Map:
+		return TypeObject
+	default:
+		return ""
+	}
+}
+
+// parseTagOptions parses the options of the goqrius tag, after the name.
+// The valid options are returned even if there is an error in the others.
+func parseTagOptions(tag string) (tagOptions, error) {
+	var (
+		opts tagOptions
+		errs []error
+	)
+
+	_, options, _ := strings.Cut(tag, ",")
+	for option := range strings.SplitSeq(options, ",") {
+		if option == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(option, "=")
+		values := strings.Split(value, "|")
+
+		switch key {
+		case "ops":
+			for _, v := range values {
+				switch operator := FilterOperator(v); operator {
+				case Eq, NotEq, GreaterThan, GreaterThanOrEqual, LessThan, LessThanOrEqual:
+					opts.operators = append(opts.operators, operator)
+				default:
+					errs = append(errs, fmt.Errorf("%w: unknown operator %q", ErrInvalidSchema, v))
+				}
+			}
+		case "alias":
+			opts.aliases = append(opts.aliases, values...)
+		case "type":
+			if FieldType(value).Operators() == nil {
+				errs = append(errs, fmt.Errorf("%w: unknown type %q", ErrInvalidSchema, value))
+
+				continue
+			}
+
+			opts.fieldType = FieldType(value)
+		case "values":
+			opts.values = append(opts.values, values...)
+		default:
+			errs = append(errs, fmt.Errorf("%w: unknown option %q", ErrInvalidSchema, key))
+		}
+	}
+
+	return opts, errors.Join(errs...)
+}
@@ -0,0 +1,221 @@
+package goqrius
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type (
+	schemaNode struct {
+		Name   string      `json:"name"`
+		Parent *schemaNode `json:"parent"`
+	}
+
+	schemaUser struct {
+		matchBase
+
+		ID       string            `goqrius:"id,type=guid"`
+		Name     string            `goqrius:"name,ops=eq|ne,alias=fullName|displayName"`
+		Age      *int              `json:"age,omitempty"`
+		Score    float64           `json:"score"`
+		Balance  *big.Int          `json:"balance"`
+		Active   bool              `json:"active"`
+		Status   string            `goqrius:"status,type=enum,values=active|inactive"`
+		Created  time.Time         `json:"created"`
+		Tags     []string          `json:"tags"`
+		Labels   map[string]string `json:"labels"`
+		Address  *matchAddress     `json:"address"`
+		Node     schemaNode        `json:"node"`
+		Any      any               `json:"any"`
+		Typed    any               `goqrius:"typed,type=int"`
+		Password string            `goqrius:"-"`
+		secret   string
+	}
+)
+
+func TestSchemaFromStruct(t *testing.T) {
+	t.Parallel()
+
+	expected := Schema{
+		"id":          {Type: TypeGUID},
+		"base_name":   {Type: TypeString},
+		"name":        {Type: TypeString, Operators: []FilterOperator{Eq, NotEq}},
+		"fullName":    {Type: TypeString, Operators: []FilterOperator{Eq, NotEq}},
+		"displayName": {Type: TypeString, Operators: []FilterOperator{Eq, NotEq}},
+		"age":         {Type: TypeInt},
+		"score":       {Type: TypeDecimal},
+		"balance":     {Type: TypeInt},
+		"active":      {Type: TypeBool},
+		"status":      {Type: TypeEnum, Values: []string{"active", "inactive"}},
+		"created":     {Type: TypeDateTime},
+		"tags":        {Type: TypeCollection},
+		"labels":      {Type: TypeObject, Dynamic: true},
+		"address":     {Type: TypeObject, Fields: Schema{"city": {Type: TypeString}, "zip": {Type: TypeInt}}},
+		"node": {Type: TypeObject, Fields: Schema{
+			"name":   {Type: TypeString},
+			"parent": {Type: TypeObject},
+		}},
+		"typed": {Type: TypeInt},
+	}
+
+	for _, f := range []func() (Schema, error){SchemaFromStruct[schemaUser], SchemaFromStruct[*schemaUser]} {
+		got, err := f()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("unexpected schema.\nexpected=%v\ngot=%v", expected, got)
+		}
+	}
+}
+
+func TestSchemaFromStructErrors(t *testing.T) {
+	t.Parallel()
+
+	type (
+		unknownOperator struct {
+			Name string `goqrius:"name,ops=eq|contains"`
+		}
+		unknownType struct {
+			Name string `goqrius:"name,type=text"`
+		}
+		unknownOption struct {
+			Name string `goqrius:"name,sortable"`
+		}
+		operatorOfType struct {
+			Active bool `goqrius:"active,ops=gt"`
+		}
+		enumWithoutValues struct {
+			Status string `goqrius:"status,type=enum"`
+		}
+		nestedError struct {
+			Inner unknownOperator `json:"inner"`
+		}
+	)
+
+	tests := map[string]func() (Schema, error){
+		"unknown operator":     SchemaFromStruct[unknownOperator],
+		"unknown type":         SchemaFromStruct[unknownType],
+		"unknown option":       SchemaFromStruct[unknownOption],
+		"operator of the type": SchemaFromStruct[operatorOfType],
+		"enum without values":  SchemaFromStruct[enumWithoutValues],
+		"nested error":         SchemaFromStruct[nestedError],
+		"not a struct":         SchemaFromStruct[map[string]any],
+	}
+
+	for name, f := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := f(); !errors.Is(err, ErrInvalidSchema) {
+				t.Fatalf("expected %v, got %v", ErrInvalidSchema, err)
+			}
+		})
+	}
+}
+
+func TestSchemaFromStructChecks(t *testing.T) {
+	t.Parallel()
+
+	schema, err := SchemaFromStruct[schemaUser]()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := map[string]struct {
+		filter   string
+		expected error
+	}{
+		"valid":                {filter: "fullName eq 'John' and age gt 18 and address.city eq 'Madrid'"},
+		"map key":              {filter: "labels.role eq 'admin' and labels.team gt 'a'"},
+		"unknown nested field": {filter: "address.country eq 'Spain'", expected: ErrUnknownField},
+		"excluded field":       {filter: "Password eq 'secret'", expected: ErrUnknownField},
+		"unexported field":     {filter: "secret eq 'secret'", expected: ErrUnknownField},
+		"operator not allowed": {filter: "name gt 'J'", expected: ErrOperatorNotAllowed},
+		"type mismatch":        {filter: "status eq 'deleted'", expected: ErrTypeMismatch},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := Check(MustParse(tt.filter), schema); !errors.Is(err, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestSchemaFromStructRecursive(t *testing.T) {
+	t.Parallel()
+
+	schema, err := SchemaFromStruct[schemaNode]()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := map[string]error{
+		"name eq 'root' and parent eq null": nil,
+		"parent.bogus eq 1":                 ErrUnknownField,
+		"parent.name eq 'root'":             ErrUnknownField,
+		"parent.parent eq null":             ErrUnknownField,
+	}
+
+	for filter, expected := range tests {
+		t.Run(filter, func(t *testing.T) {
+			t.Parallel()
+
+			if err := Check(MustParse(filter), schema); !errors.Is(err, expected) {
+				t.Fatalf("expected %v, got %v", expected, err)
+			}
+		})
+	}
+}
+
+func TestSchemaFromStructAliasCollision(t *testing.T) {
+	t.Parallel()
+
+	type named struct {
+		Name     string `json:"name"`
+		FullName string `goqrius:"fullName,alias=name|display"`
+	}
+
+	schema, err := SchemaFromStruct[named]()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := Schema{"name": {Type: TypeString}, "fullName": {Type: TypeString}, "display": {Type: TypeString}}
+	if !reflect.DeepEqual(schema, expected) {
+		t.Fatalf("unexpected schema.\nexpected=%v\ngot=%v", expected, schema)
+	}
+
+	v := named{Name: "John", FullName: "John Doe"}
+
+	got, err := Match(MustParse("name eq 'John' and display eq 'John Doe'"), v)
+	if err != nil || !got {
+		t.Fatalf("expected the field name to win over the alias, got %t, %v", got, err)
+	}
+}
+
+func TestMatchAlias(t *testing.T) {
+	t.Parallel()
+
+	user := schemaUser{Name: "John"}
+
+	for _, filter := range []string{"name eq 'John'", "fullName eq 'John'", "displayName eq 'John'"} {
+		got, err := Match(MustParse(filter), user)
+		if err != nil || !got {
+			t.Fatalf("expected %q to match, got %t, %v", filter, got, err)
+		}
+
+		predicate, err := Compile[schemaUser](MustParse(filter))
+		if err != nil || !predicate(user) {
+			t.Fatalf("expected %q to compile and match, got %v", filter, err)
+		}
+	}
+}
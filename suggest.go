@@ -0,0 +1,123 @@
+package goqrius
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/golaxo/goqrius/internal/token"
+)
+
+const maxSuggestions = 3
+
+//nolint:gochecknoglobals // keywords of the language, used for suggestions.
+var keywords = []string{
+	string(token.Eq),
+	string(token.NotEq),
+	string(token.GreaterThan),
+	string(token.GreaterThanOrEqual),
+	string(token.LessThan),
+	string(token.LessThanOrEqual),
+	string(token.And),
+	string(token.Or),
+	string(token.Not),
+	string(token.Null),
+	string(token.True),
+	string(token.False),
+}
+
+// keywordAliases are common spellings of the keywords that are too far away to be found by edit distance.
+//
+//nolint:gochecknoglobals // lookup table for suggestions.
+var keywordAliases = map[string]string{
+	"equal":              string(token.Eq),
+	"equals":             string(token.Eq),
+	"notequal":           string(token.NotEq),
+	"notequals":          string(token.NotEq),
+	"neq":                string(token.NotEq),
+	"greater":            string(token.GreaterThan),
+	"greaterthan":        string(token.GreaterThan),
+	"greaterorequal":     string(token.GreaterThanOrEqual),
+	"greaterthanorequal": string(token.GreaterThanOrEqual),
+	"gte":                string(token.GreaterThanOrEqual),
+	"less":               string(token.LessThan),
+	"lessthan":           string(token.LessThan),
+	"lessorequal":        string(token.LessThanOrEqual),
+	"lessthanorequal":    string(token.LessThanOrEqual),
+	"lte":                string(token.LessThanOrEqual),
+	"nil":                string(token.Null),
+	"none":               string(token.Null),
+}
+
+// suggest returns the candidates closest to word, based on the edit distance, sorted by closeness.
+func suggest(word string, candidates []string) []string {
+	lower := strings.ToLower(word)
+
+	type scored struct {
+		candidate string
+		distance  int
+	}
+
+	threshold := max(1, len(lower)/3)
+
+	var found []scored
+
+	for _, c := range candidates {
+		if c == word {
+			continue
+		}
+
+		if d := editDistance(lower, strings.ToLower(c)); d <= threshold {
+			found = append(found, scored{candidate: c, distance: d})
+		}
+	}
+
+	slices.SortStableFunc(found, func(a, b scored) int { return a.distance - b.distance })
+
+	suggestions := make([]string, 0, min(len(found), maxSuggestions))
+	for _, s := range found[:min(len(found), maxSuggestions)] {
+		suggestions = append(suggestions, s.candidate)
+	}
+
+	return suggestions
+}
+
+// suggestKeywords returns the keywords that word may be a misspelling of.
+func suggestKeywords(word string) []string {
+	if k, ok := keywordAliases[strings.ToLower(word)]; ok {
+		return []string{k}
+	}
+
+	return suggest(word, keywords)
+}
+
+// editDistance returns the optimal string alignment distance between a and b,
+// i.e. the Levenshtein distance also counting adjacent transpositions as a single edit.
+func editDistance(a, b string) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return prev[len(b)]
+}
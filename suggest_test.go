@@ -0,0 +1,114 @@
+package goqrius
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestSuggestions(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input               string
+		opts                []ParseOption
+		expectedCode        ErrorCode
+		expectedSuggestions []string
+	}{
+		"misspelled operator": {
+			input:               "name eqq 'x'",
+			expectedCode:        CodeExpectedOperator,
+			expectedSuggestions: []string{"eq"},
+		},
+		"spelled out operator": {
+			input:               "name equals 'x'",
+			expectedCode:        CodeExpectedOperator,
+			expectedSuggestions: []string{"eq"},
+		},
+		"upper case operator": {
+			input:               "name EQ 'x'",
+			expectedCode:        CodeExpectedOperator,
+			expectedSuggestions: []string{"eq"},
+		},
+		"misspelled null": {
+			input:               "name eq nul",
+			expectedCode:        CodeIdentifierAsValue,
+			expectedSuggestions: []string{"null"},
+		},
+		"misspelled logical operator": {
+			input:               "name eq 'x' adn age gt 1",
+			expectedCode:        CodeUnexpectedToken,
+			expectedSuggestions: []string{"and"},
+		},
+		"unknown field": {
+			input:               "nmae eq 'x'",
+			opts:                []ParseOption{WithFields("name", "age")},
+			expectedCode:        CodeUnknownField,
+			expectedSuggestions: []string{"name"},
+		},
+		"unknown field without close matches": {
+			input:        "email eq 'x'",
+			opts:         []ParseOption{WithFields("name", "age")},
+			expectedCode: CodeUnknownField,
+		},
+		"no suggestion for unrelated word": {
+			input:        "name is null",
+			expectedCode: CodeExpectedOperator,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(tt.input, tt.opts...)
+
+			var ute UnexpectedTokenError
+			if !errors.As(err, &ute) {
+				t.Fatalf("expected UnexpectedTokenError, got %v", err)
+			}
+
+			if ute.Code != tt.expectedCode {
+				t.Fatalf("expected code %q, got %q", tt.expectedCode, ute.Code)
+			}
+
+			if !slices.Equal(ute.Suggestions, tt.expectedSuggestions) {
+				t.Fatalf("expected suggestions %v, got %v", tt.expectedSuggestions, ute.Suggestions)
+			}
+		})
+	}
+}
+
+func TestParseWithKnownFields(t *testing.T) {
+	t.Parallel()
+
+	_, err := Parse("name eq 'x' and age gt 18", WithFields("name", "age"))
+	if err != nil {
+		t.Fatalf("err not expected; error=%v", err)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		a, b     string
+		expected int
+	}{
+		"equal":         {a: "name", b: "name", expected: 0},
+		"substitution":  {a: "name", b: "nane", expected: 1},
+		"insertion":     {a: "eq", b: "eqq", expected: 1},
+		"transposition": {a: "nmae", b: "name", expected: 1},
+		"empty":         {a: "", b: "and", expected: 3},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := editDistance(tt.a, tt.b); got != tt.expected {
+				t.Fatalf("expected distance %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package goqrius
+
+import (
+	"fmt"
+	"iter"
+
+	"github.com/golaxo/goqrius/internal/lexer"
+	"github.com/golaxo/goqrius/internal/token"
+)
+
+// Tokens returns an iterator over the tokens of the input filter expression.
+// The tokens are read lazily, and the iteration finishes before token.EOF.
+// An illegal token is yielded together with an UnexpectedTokenError.
+// The options are the ones of Parse, so the tokens are the same the parser reads, e.g. with
+// WithCaseInsensitiveKeywords, and the ones that don't change the tokens are ignored.
+func Tokens(input string, opts ...ParseOption) iter.Seq2[token.Token, error] {
+	lexerOptions := newParseOptions(opts...).lexerOptions()
+
+	return func(yield func(token.Token, error) bool) {
+		l := lexer.New(input, lexerOptions...)
+
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+			var err error
+			if tok.Type == token.Illegal {
+				err = UnexpectedTokenError{
+					Token:   tok,
+					Code:    CodeIllegalToken,
+					Message: fmt.Sprintf("illegal token %q", tok.Literal),
+				}
+			}
+
+			if !yield(tok, err) {
+				return
+			}
+		}
+	}
+}
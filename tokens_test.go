@@ -0,0 +1,105 @@
+package goqrius
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golaxo/goqrius/internal/token"
+)
+
+func TestTokens(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input          string
+		opts           []ParseOption
+		expectedTypes  []token.Type
+		expectedErrors int
+	}{
+		"empty input": {
+			input:         "",
+			expectedTypes: nil,
+		},
+		"simple filter": {
+			input:         "name eq 'John'",
+			expectedTypes: []token.Type{token.Ident, token.Eq, token.String},
+		},
+		"grouped filter": {
+			input: "not (age gt 18 or name eq null)",
+			expectedTypes: []token.Type{
+				token.Not, token.Lparen, token.Ident, token.GreaterThan, token.Int,
+				token.Or, token.Ident, token.Eq, token.Null, token.Rparen,
+			},
+		},
+		"uppercase keywords": {
+			input:         "a EQ 1 AND b eq 2",
+			expectedTypes: []token.Type{token.Ident, token.Ident, token.Int, token.Ident, token.Ident, token.Eq, token.Int},
+		},
+		"case insensitive keywords": {
+			input: "a EQ 1 AND b eq 2",
+			opts:  []ParseOption{WithCaseInsensitiveKeywords()},
+			expectedTypes: []token.Type{
+				token.Ident, token.Eq, token.Int, token.And, token.Ident, token.Eq, token.Int,
+			},
+		},
+		"illegal character": {
+			input:          "n@me eq 1",
+			expectedTypes:  []token.Type{token.Ident, token.Illegal, token.Ident, token.Eq, token.Int},
+			expectedErrors: 1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				types []token.Type
+				errs  int
+			)
+
+			for tok, err := range Tokens(tt.input, tt.opts...) {
+				types = append(types, tok.Type)
+
+				if err != nil {
+					errs++
+
+					var ute UnexpectedTokenError
+					if !errors.As(err, &ute) || ute.Token != tok {
+						t.Fatalf("expected UnexpectedTokenError for token %v, got %v", tok, err)
+					}
+				}
+			}
+
+			if len(types) != len(tt.expectedTypes) {
+				t.Fatalf("expected %d tokens, got %d: %v", len(tt.expectedTypes), len(types), types)
+			}
+
+			for i := range types {
+				if types[i] != tt.expectedTypes[i] {
+					t.Fatalf("expected token [%d] to be %q, got %q", i, tt.expectedTypes[i], types[i])
+				}
+			}
+
+			if errs != tt.expectedErrors {
+				t.Fatalf("expected %d errors, got %d", tt.expectedErrors, errs)
+			}
+		})
+	}
+}
+
+func TestTokensStopEarly(t *testing.T) {
+	t.Parallel()
+
+	var count int
+	for range Tokens("a eq 1 and b eq 2") {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+
+	if count != 2 {
+		t.Fatalf("expected iteration to stop after 2 tokens, got %d", count)
+	}
+}
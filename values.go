@@ -0,0 +1,92 @@
+package goqrius
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+// Int64 returns the integer as an int64.
+// The error wraps ErrInvalidValue, and strconv.ErrRange if it doesn't fit in an int64, see WithInt64Range.
+func (il *IntegerLiteral) Int64() (int64, error) {
+	i, err := strconv.ParseInt(il.Value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidValue, err)
+	}
+
+	return i, nil
+}
+
+// BigInt returns the integer as a big.Int, that can hold integers of any size.
+func (il *IntegerLiteral) BigInt() (*big.Int, error) {
+	i, ok := new(big.Int).SetString(il.Value, 10)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q is not an integer", ErrInvalidValue, il.Value)
+	}
+
+	return i, nil
+}
+
+// Float64 returns the integer as a float64, that may lose precision for big integers.
+func (il *IntegerLiteral) Float64() (float64, error) {
+	f, _, err := big.ParseFloat(il.Value, 10, 64, big.ToNearestEven)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not an integer", ErrInvalidValue, il.Value)
+	}
+
+	v, _ := f.Float64()
+
+	return v, nil
+}
+
+// GoValue returns the integer as an int64, see Int64.
+func (il *IntegerLiteral) GoValue() (any, error) {
+	i, err := il.Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// Time returns the string as a time.Time, parsed with the RFC 3339 format, e.g. `'2025-01-02T15:04:05Z'`.
+func (sl *StringLiteral) Time() (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, sl.Value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %w", ErrInvalidValue, err)
+	}
+
+	return t, nil
+}
+
+// GoValue returns the string.
+func (sl *StringLiteral) GoValue() (any, error) {
+	return sl.Value, nil
+}
+
+// Bool returns the boolean.
+func (bl *BooleanLiteral) Bool() bool {
+	return bl.Value
+}
+
+// GoValue returns the boolean.
+func (bl *BooleanLiteral) GoValue() (any, error) {
+	return bl.Value, nil
+}
+
+// GoValue returns nil.
+func (n *Null) GoValue() (any, error) {
+	//nolint:nilnil // nil is the Go value of null.
+	return nil, nil
+}
+
+// GoValue returns ErrInvalidValue, the placeholders have no value.
+func (be *BadExpr) GoValue() (any, error) {
+	return nil, fmt.Errorf("%w: %s", ErrInvalidValue, be)
+}
+
+// GoValue returns ErrInvalidValue, the placeholders have no value.
+func (me *MissingExpr) GoValue() (any, error) {
+	return nil, fmt.Errorf("%w: %s", ErrInvalidValue, me)
+}
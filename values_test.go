@@ -0,0 +1,148 @@
+package goqrius
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestIntegerLiteral(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		value       string
+		expected    int64
+		expectedErr error
+		float       float64
+	}{
+		"positive":     {value: "18", expected: 18, float: 18},
+		"max int64":    {value: "9223372036854775807", expected: 9223372036854775807, float: 9223372036854775807},
+		"out of range": {value: "99999999999999999999", expectedErr: strconv.ErrRange, float: 99999999999999999999},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			il := &IntegerLiteral{Value: tt.value}
+
+			got, err := il.Int64()
+			if !errors.Is(err, tt.expectedErr) || got != tt.expected {
+				t.Fatalf("unexpected Int64. expected=%d, %v got=%d, %v", tt.expected, tt.expectedErr, got, err)
+			}
+
+			bi, err := il.BigInt()
+			if err != nil || bi.String() != tt.value {
+				t.Fatalf("unexpected BigInt. expected=%s got=%v, %v", tt.value, bi, err)
+			}
+
+			f, err := il.Float64()
+			if err != nil || f != tt.float {
+				t.Fatalf("unexpected Float64. expected=%v got=%v, %v", tt.float, f, err)
+			}
+		})
+	}
+}
+
+func TestIntegerLiteralInvalid(t *testing.T) {
+	t.Parallel()
+
+	il := &IntegerLiteral{Value: "1x"}
+
+	if _, err := il.Int64(); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("expected ErrInvalidValue, got %v", err)
+	}
+
+	if _, err := (&IntegerLiteral{Value: "99999999999999999999"}).Int64(); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("expected ErrInvalidValue, got %v", err)
+	}
+
+	if _, err := il.BigInt(); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("expected ErrInvalidValue, got %v", err)
+	}
+
+	if _, err := il.Float64(); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("expected ErrInvalidValue, got %v", err)
+	}
+}
+
+func TestStringLiteralTime(t *testing.T) {
+	t.Parallel()
+
+	got, err := (&StringLiteral{Value: "2025-01-02T15:04:05+02:00"}).Time()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := time.Date(2025, 1, 2, 13, 4, 5, 0, time.UTC); !got.Equal(expected) {
+		t.Fatalf("unexpected time. expected=%v got=%v", expected, got)
+	}
+
+	if _, err = (&StringLiteral{Value: "2025-01-02"}).Time(); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("expected ErrInvalidValue for a date without time, got %v", err)
+	}
+}
+
+func TestGoValue(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		value       Value
+		expected    any
+		expectedErr error
+	}{
+		"integer":     {value: &IntegerLiteral{Value: "18"}, expected: int64(18)},
+		"big integer": {value: &IntegerLiteral{Value: "99999999999999999999"}, expectedErr: strconv.ErrRange},
+		"string":      {value: &StringLiteral{Value: "John"}, expected: "John"},
+		"boolean":     {value: &BooleanLiteral{Value: true}, expected: true},
+		"null":        {value: &Null{}, expected: nil},
+		"bad":         {value: &BadExpr{}, expectedErr: ErrInvalidValue},
+		"missing":     {value: &MissingExpr{}, expectedErr: ErrInvalidValue},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.value.GoValue()
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if got != tt.expected {
+				t.Fatalf("unexpected value. expected=%v got=%v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestBooleanLiteral(t *testing.T) {
+	t.Parallel()
+
+	expr := MustParse("active eq true")
+
+	filter, _ := expr.(*FilterExpr)
+
+	b, ok := filter.Right.(*BooleanLiteral)
+	if !ok || !b.Bool() {
+		t.Fatalf("expected a true BooleanLiteral, got %#v", filter.Right)
+	}
+
+	if !Equal(expr, Field("active").Eq(true)) {
+		t.Fatalf("built expression is not equal to the parsed one")
+	}
+
+	if Hash(expr) == Hash(MustParse("active eq false")) {
+		t.Fatalf("expected different hashes for true and false")
+	}
+
+	if got := Format(expr); got != "active eq true" {
+		t.Fatalf("unexpected format %q", got)
+	}
+
+	data, _ := MarshalBinary(expr)
+	if decoded, err := UnmarshalBinary(data); err != nil || !Equal(decoded, expr) {
+		t.Fatalf("unexpected binary round trip %v, %v", decoded, err)
+	}
+}
@@ -0,0 +1,107 @@
+package goqrius
+
+import (
+	"fmt"
+)
+
+// Visitor has a typed method for every node type, so a data layer only needs to implement
+// how to translate each node, see Accept.
+type Visitor[R any] interface {
+	VisitAnd(expr *AndExpr) R
+	VisitOr(expr *OrExpr) R
+	VisitNot(expr *NotExpr) R
+	VisitFilter(expr *FilterExpr) R
+	VisitIdentifier(expr *Identifier) R
+	VisitIntegerLiteral(expr *IntegerLiteral) R
+	VisitStringLiteral(expr *StringLiteral) R
+	VisitBooleanLiteral(expr *BooleanLiteral) R
+	VisitNull(expr *Null) R
+	VisitBad(expr *BadExpr) R
+	VisitMissing(expr *MissingExpr) R
+}
+
+// Accept calls the method of the Visitor matching the type of expr.
+// The visitor is responsible for visiting the children of the node, e.g. by calling Accept with them.
+// If expr is nil, the zero value of R is returned.
+func Accept[R any](expr Expression, v Visitor[R]) R {
+	switch e := expr.(type) {
+	case nil:
+		var zero R
+
+		return zero
+	case *AndExpr:
+		return v.VisitAnd(e)
+	case *OrExpr:
+		return v.VisitOr(e)
+	case *NotExpr:
+		return v.VisitNot(e)
+	case *FilterExpr:
+		return v.VisitFilter(e)
+	case *Identifier:
+		return v.VisitIdentifier(e)
+	case *IntegerLiteral:
+		return v.VisitIntegerLiteral(e)
+	case *StringLiteral:
+		return v.VisitStringLiteral(e)
+	case *BooleanLiteral:
+		return v.VisitBooleanLiteral(e)
+	case *Null:
+		return v.VisitNull(e)
+	case *BadExpr:
+		return v.VisitBad(e)
+	case *MissingExpr:
+		return v.VisitMissing(e)
+	default:
+		panic(fmt.Sprintf("goqrius: unexpected expression type %T", expr))
+	}
+}
+
+// Walk traverses expr in depth-first order.
+// pre is called for every node before its children, if it returns false the children of the node
+// and post are skipped for that node.
+// post is called for every node after its children.
+// Either pre or post can be nil.
+func Walk(expr Expression, pre func(Expression) bool, post func(Expression)) {
+	if expr == nil {
+		return
+	}
+
+	if pre != nil && !pre(expr) {
+		return
+	}
+
+	for _, child := range children(expr) {
+		Walk(child, pre, post)
+	}
+
+	if post != nil {
+		post(expr)
+	}
+}
+
+// Inspect traverses expr in depth-first order, as ast.Inspect does:
+// it starts by calling f(expr), if f returns true, Inspect is called for each of the children of expr,
+// followed by a call of f(nil).
+func Inspect(expr Expression, f func(Expression) bool) {
+	Walk(expr, f, func(Expression) { f(nil) })
+}
+
+// children returns the direct children of the expression, from left to right.
+func children(expr Expression) []Expression {
+	switch e := expr.(type) {
+	case *AndExpr:
+		return []Expression{e.Left, e.Right}
+	case *OrExpr:
+		return []Expression{e.Left, e.Right}
+	case *NotExpr:
+		return []Expression{e.Right}
+	case *FilterExpr:
+		if e.Left == nil {
+			return []Expression{e.Right}
+		}
+
+		return []Expression{e.Left, e.Right}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,139 @@
+package goqrius
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	expr := MustParse("not name eq 'John' or age gt 18")
+
+	var pre, post []string
+
+	Walk(expr, func(e Expression) bool {
+		pre = append(pre, nodeName(e))
+
+		return true
+	}, func(e Expression) {
+		post = append(post, nodeName(e))
+	})
+
+	expectedPre := []string{"or", "not", "filter", "name", "'John'", "filter", "age", "18"}
+	if !slices.Equal(pre, expectedPre) {
+		t.Fatalf("unexpected pre-order. expected=%v got=%v", expectedPre, pre)
+	}
+
+	expectedPost := []string{"name", "'John'", "filter", "not", "age", "18", "filter", "or"}
+	if !slices.Equal(post, expectedPost) {
+		t.Fatalf("unexpected post-order. expected=%v got=%v", expectedPost, post)
+	}
+}
+
+func TestWalkSkipChildren(t *testing.T) {
+	t.Parallel()
+
+	expr := MustParse("not name eq 'John' or age gt 18")
+
+	var visited []string
+
+	Walk(expr, func(e Expression) bool {
+		visited = append(visited, nodeName(e))
+		_, isNot := e.(*NotExpr)
+
+		return !isNot
+	}, nil)
+
+	expected := []string{"or", "not", "filter", "age", "18"}
+	if !slices.Equal(visited, expected) {
+		t.Fatalf("unexpected visited nodes. expected=%v got=%v", expected, visited)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	t.Parallel()
+
+	var identifiers []string
+
+	Inspect(MustParse("name eq 'John' and (age gt 18 or email eq null)"), func(e Expression) bool {
+		if i, ok := e.(*Identifier); ok {
+			identifiers = append(identifiers, i.Value)
+		}
+
+		return true
+	})
+
+	expected := []string{"name", "age", "email"}
+	if !slices.Equal(identifiers, expected) {
+		t.Fatalf("unexpected identifiers. expected=%v got=%v", expected, identifiers)
+	}
+}
+
+func TestAccept(t *testing.T) {
+	t.Parallel()
+
+	expr, _ := Parse("not name eq 'John' and (age gt 18 or email eq null) or 1 gt")
+
+	expected := "((NOT name = \"John\" AND (age > 18 OR email IS NULL)) OR <bad>)"
+	if got := Accept[string](expr, sqlVisitor{}); got != expected {
+		t.Fatalf("unexpected visitor result. expected=%q got=%q", expected, got)
+	}
+}
+
+func nodeName(e Expression) string {
+	switch e.(type) {
+	case *AndExpr:
+		return "and"
+	case *OrExpr:
+		return "or"
+	case *NotExpr:
+		return "not"
+	case *FilterExpr:
+		return "filter"
+	default:
+		return e.String()
+	}
+}
+
+// sqlVisitor is an example of a Visitor translating an Expression to a SQL like condition.
+type sqlVisitor struct{}
+
+func (v sqlVisitor) VisitAnd(e *AndExpr) string {
+	return "(" + Accept[string](e.Left, v) + " AND " + Accept[string](e.Right, v) + ")"
+}
+
+func (v sqlVisitor) VisitOr(e *OrExpr) string {
+	return "(" + Accept[string](e.Left, v) + " OR " + Accept[string](e.Right, v) + ")"
+}
+
+func (v sqlVisitor) VisitNot(e *NotExpr) string { return "NOT " + Accept[string](e.Right, v) }
+
+func (v sqlVisitor) VisitFilter(e *FilterExpr) string {
+	if _, isNull := e.Right.(*Null); isNull {
+		if e.Operator == NotEq {
+			return Accept[string](e.Left, v) + " IS NOT NULL"
+		}
+
+		return Accept[string](e.Left, v) + " IS NULL"
+	}
+
+	operators := map[FilterOperator]string{
+		Eq: "=", NotEq: "<>", GreaterThan: ">", GreaterThanOrEqual: ">=", LessThan: "<", LessThanOrEqual: "<=",
+	}
+
+	return Accept[string](e.Left, v) + " " + operators[e.Operator] + " " + Accept[string](e.Right, v)
+}
+
+func (v sqlVisitor) VisitIdentifier(e *Identifier) string         { return e.Value }
+func (v sqlVisitor) VisitIntegerLiteral(e *IntegerLiteral) string { return e.Value }
+
+func (v sqlVisitor) VisitStringLiteral(e *StringLiteral) string {
+	return `"` + strings.ReplaceAll(e.Value, `"`, `""`) + `"`
+}
+
+func (v sqlVisitor) VisitBooleanLiteral(e *BooleanLiteral) string { return strings.ToUpper(e.String()) }
+func (v sqlVisitor) VisitNull(*Null) string                       { return "NULL" }
+func (v sqlVisitor) VisitBad(*BadExpr) string                     { return "<bad>" }
+func (v sqlVisitor) VisitMissing(*MissingExpr) string             { return "<missing>" }